@@ -0,0 +1,249 @@
+package scylla
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+type BatchType = frame.BatchTypeFlag
+
+const (
+	LoggedBatch   BatchType = frame.LoggedBatchFlag
+	UnloggedBatch BatchType = frame.UnloggedBatchFlag
+	CounterBatch  BatchType = frame.CounterBatchFlag
+)
+
+// Batch groups raw and prepared queries that are sent to the cluster as a single BATCH request.
+type Batch struct {
+	session *Session
+	stmt    transport.BatchStatement
+	buf     frame.Buffer
+
+	err []error
+}
+
+func (s *Session) Batch(kind BatchType) *Batch {
+	return &Batch{
+		session: s,
+		stmt: transport.BatchStatement{
+			Type:        kind,
+			Consistency: s.cfg.DefaultConsistency,
+		},
+	}
+}
+
+// Add appends q together with its currently bound values to the batch,
+// later binds on q don't affect the batch.
+func (b *Batch) Add(q Query) *Batch {
+	if q.err != nil {
+		b.err = append(b.err, q.err...)
+		return b
+	}
+	// Servers ignore names of values in BATCH, see CASSANDRA-10246.
+	if q.stmt.Names != nil {
+		b.err = append(b.err, fmt.Errorf("can't add %q to batch, named values aren't supported in batches", q.stmt.Content))
+		return b
+	}
+
+	b.stmt.Statements = append(b.stmt.Statements, q.stmt.Clone())
+	return b
+}
+
+func (b *Batch) Size() int {
+	return len(b.stmt.Statements)
+}
+
+func (b *Batch) SetConsistency(v frame.Consistency) {
+	b.stmt.Consistency = v
+}
+
+func (b *Batch) Consistency() frame.Consistency {
+	return b.stmt.Consistency
+}
+
+func (b *Batch) SetSerialConsistency(v frame.Consistency) {
+	b.stmt.SerialConsistency = v
+}
+
+func (b *Batch) SerialConsistency() frame.Consistency {
+	return b.stmt.SerialConsistency
+}
+
+// SetTimestamp sets the default timestamp in microseconds for all statements in the batch,
+// overriding the session's TimestampGenerator. If v is 0, the timestamp is generated again on each execution.
+func (b *Batch) SetTimestamp(v int64) {
+	b.stmt.Timestamp = v
+}
+
+func (b *Batch) Timestamp() int64 {
+	return b.stmt.Timestamp
+}
+
+// timestamp returns timestamp set by SetTimestamp or a new one from the session's generator.
+func (b *Batch) timestamp() frame.Long {
+	if b.stmt.Timestamp == 0 && b.session.cfg.TimestampGenerator != nil {
+		return b.session.cfg.TimestampGenerator.Next()
+	}
+	return b.stmt.Timestamp
+}
+
+func (b *Batch) SetCompression(v bool) {
+	b.stmt.Compression = v
+}
+
+func (b *Batch) Compression() bool {
+	return b.stmt.Compression
+}
+
+// SetTimeout limits time of waiting for the response, overriding the session's RequestTimeout.
+func (b *Batch) SetTimeout(v time.Duration) {
+	b.stmt.Timeout = v
+}
+
+func (b *Batch) Timeout() time.Duration {
+	return b.stmt.Timeout
+}
+
+// SetTracing enables tracing of the batch, ID of the trace is returned in Result.TracingID.
+func (b *Batch) SetTracing(v bool) {
+	b.stmt.Tracing = v
+}
+
+func (b *Batch) Tracing() bool {
+	return b.stmt.Tracing
+}
+
+// SetCustomPayload sets the custom payload sent with the batch, the map mustn't be modified afterwards.
+func (b *Batch) SetCustomPayload(v map[string][]byte) {
+	b.stmt.CustomPayload = v
+}
+
+func (b *Batch) CustomPayload() map[string][]byte {
+	return b.stmt.CustomPayload
+}
+
+func (b *Batch) SetIdempotent(v bool) {
+	b.stmt.Idempotent = v
+}
+
+func (b *Batch) Idempotent() bool {
+	return b.stmt.Idempotent
+}
+
+func (b *Batch) Exec(ctx context.Context) (Result, error) {
+	if b.err != nil {
+		return Result{}, fmt.Errorf("batch can't be executed: %v", b.err)
+	}
+	if len(b.stmt.Statements) == 0 {
+		return Result{}, fmt.Errorf("batch can't be executed: no statements")
+	}
+
+	info, err := b.info()
+	if err != nil {
+		return Result{}, err
+	}
+
+	stmt := b.stmt
+	stmt.Timestamp = b.timestamp()
+	res, err := b.session.execute(ctx, info, stmt.Idempotent, stmt.Consistency, b.session.cfg.SpeculativeExecutionPolicy,
+		b.execution(&stmt))
+	if err != nil {
+		return Result{}, err
+	}
+	b.session.handleWarnings(b.content(), &res)
+	return b.session.result(res), nil
+}
+
+// ExecCAS executes a batch containing lightweight transactions and reports whether it was applied.
+// If it wasn't, previous holds the existing values of rows targeted by the conditions, without the [applied] column.
+// Serial phase of the transaction uses consistency set by SetSerialConsistency,
+// as lightweight transactions aren't idempotent the batch is retried on a different node only
+// if the coordinator rejected it without executing, i.e. on unavailable and bootstrapping errors.
+func (b *Batch) ExecCAS(ctx context.Context) (applied bool, previous []frame.Row, err error) {
+	if b.err != nil {
+		return false, nil, fmt.Errorf("batch can't be executed: %v", b.err)
+	}
+	if len(b.stmt.Statements) == 0 {
+		return false, nil, fmt.Errorf("batch can't be executed: no statements")
+	}
+
+	info, err := b.info()
+	if err != nil {
+		return false, nil, err
+	}
+
+	stmt := b.stmt
+	stmt.Timestamp = b.timestamp()
+	res, err := b.session.execute(ctx, info, false, stmt.Consistency, nil,
+		b.execution(&stmt))
+	if err != nil {
+		return false, nil, err
+	}
+	b.session.handleWarnings(b.content(), &res)
+
+	return casResult(res)
+}
+
+// content returns text of the batch as it would be written in CQL.
+func (b *Batch) content() string {
+	var sb strings.Builder
+	sb.WriteString("BEGIN BATCH ")
+	for i := range b.stmt.Statements {
+		sb.WriteString(b.stmt.Statements[i].Content)
+		sb.WriteString("; ")
+	}
+	sb.WriteString("APPLY BATCH")
+	return sb.String()
+}
+
+// execution returns execution of stmt, speculative executions execute clones of stmt.
+// Statements of the batch that aren't prepared are skipped when preparing them again.
+func (b *Batch) execution(stmt *transport.BatchStatement) execution {
+	prepared := make([]*transport.Statement, len(stmt.Statements))
+	for i := range stmt.Statements {
+		prepared[i] = &stmt.Statements[i]
+	}
+	return execution{
+		exec: func(ctx context.Context, conn *transport.Conn) (transport.QueryResult, error) {
+			return conn.Batch(ctx, *stmt)
+		},
+		prepared: prepared,
+		clone: func() execution {
+			c := stmt.Clone()
+			return b.execution(&c)
+		},
+	}
+}
+
+// info returns token aware query info only if all statements in the batch target the same partition.
+func (b *Batch) info() (transport.QueryInfo, error) {
+	token, ks, lwt, ok := batchRouting(&b.buf, b.stmt.Statements)
+	if !ok {
+		return b.session.cluster.NewQueryInfo(), nil
+	}
+	if lwt {
+		return b.session.cluster.NewLWTQueryInfo(token, ks)
+	}
+	return b.session.cluster.NewTokenAwareQueryInfo(token, ks)
+}
+
+// batchRouting returns token and keyspace shared by all stmts and reports whether any of them is conditional,
+// ok is false if the statements can't be routed together by token.
+func batchRouting(buf *frame.Buffer, stmts []transport.Statement) (token transport.Token, ks string, lwt, ok bool) {
+	ks = stmts[0].Keyspace
+	for i := range stmts {
+		t, ok := statementToken(buf, &stmts[i])
+		// Replicas of the same token differ between keyspaces with different replication.
+		if !ok || (i > 0 && t != token) || stmts[i].Keyspace != ks {
+			return 0, "", false, false
+		}
+		token = t
+		lwt = lwt || stmts[i].LWT
+	}
+	return token, ks, lwt, true
+}
@@ -0,0 +1,78 @@
+package scylla
+
+import (
+	"testing"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+// routedStmt returns statement of keyspace ks with a partition key of one column set to pk.
+func routedStmt(ks string, pk byte, lwt bool) transport.Statement {
+	return transport.Statement{
+		Keyspace:  ks,
+		Values:    []frame.Value{{N: 1, Bytes: []byte{pk}}, {N: 1, Bytes: []byte{0}}},
+		PkIndexes: []frame.Short{0},
+		PkCnt:     1,
+		LWT:       lwt,
+	}
+}
+
+func TestBatchRouting(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		stmts []transport.Statement
+		ks    string
+		lwt   bool
+		ok    bool
+	}{
+		{
+			name:  "single statement",
+			stmts: []transport.Statement{routedStmt("ks", 1, false)},
+			ks:    "ks",
+			ok:    true,
+		},
+		{
+			name:  "same partition",
+			stmts: []transport.Statement{routedStmt("ks", 1, false), routedStmt("ks", 1, false)},
+			ks:    "ks",
+			ok:    true,
+		},
+		{
+			name:  "conditional statement",
+			stmts: []transport.Statement{routedStmt("ks", 1, false), routedStmt("ks", 1, true)},
+			ks:    "ks",
+			lwt:   true,
+			ok:    true,
+		},
+		{
+			name:  "different partitions",
+			stmts: []transport.Statement{routedStmt("ks", 1, false), routedStmt("ks", 2, false)},
+		},
+		{
+			name:  "different keyspaces",
+			stmts: []transport.Statement{routedStmt("ks", 1, false), routedStmt("other", 1, false)},
+		},
+		{
+			name:  "unprepared statement",
+			stmts: []transport.Statement{routedStmt("ks", 1, false), {Content: "INSERT INTO ks.t (pk) VALUES (1)"}},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var buf frame.Buffer
+			token, ks, lwt, ok := batchRouting(&buf, tc.stmts)
+			if ok != tc.ok || ks != tc.ks || lwt != tc.lwt {
+				t.Fatalf("expected (%q, %v, %v), got (%q, %v, %v)", tc.ks, tc.lwt, tc.ok, ks, lwt, ok)
+			}
+			if expected, _ := statementToken(&buf, &tc.stmts[0]); ok && token != expected {
+				t.Fatalf("expected token %d, got %d", expected, token)
+			}
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package scylla
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+)
+
+// Typed binders encode v as the value of the bind marker at pos, v must match the CQL type of the marker.
+// Unprepared queries have no bind markers metadata, v is encoded as its natural CQL type then.
+
+func (q *Query) BindText(pos int, v string) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindBlob(pos int, v []byte) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindBool(pos int, v bool) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindInt32(pos int, v int32) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindInt16(pos int, v int16) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindInt8(pos int, v int8) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindFloat32(pos int, v float32) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindFloat64(pos int, v float64) *Query {
+	return q.bind(pos, v)
+}
+
+// BindUUID binds v to uuid or timeuuid bind marker, timeuuids must be version 1 UUIDs.
+func (q *Query) BindUUID(pos int, v [16]byte) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindIP(pos int, v net.IP) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindTimestamp(pos int, v time.Time) *Query {
+	return q.bind(pos, frame.CqlFromTimestamp(v))
+}
+
+// BindDate binds the calendar date of v in its location.
+func (q *Query) BindDate(pos int, v time.Time) *Query {
+	c, err := frame.CqlFromDate(v)
+	if err != nil {
+		q.err = append(q.err, fmt.Errorf("bind %d: %w", pos, err))
+		return q
+	}
+	return q.bind(pos, c)
+}
+
+// BindTime binds time of day v, that is time since midnight.
+func (q *Query) BindTime(pos int, v time.Duration) *Query {
+	return q.bind(pos, v)
+}
+
+func (q *Query) BindDuration(pos int, v frame.Duration) *Query {
+	return q.bind(pos, v)
+}
+
+// BindVarint binds v to varint bind marker, nil v is bound as null.
+func (q *Query) BindVarint(pos int, v *big.Int) *Query {
+	return q.bind(pos, v)
+}
+
+// BindDecimal binds decimal unscaled * 10^-scale, nil unscaled is bound as zero.
+func (q *Query) BindDecimal(pos int, unscaled *big.Int, scale int32) *Query {
+	return q.bind(pos, frame.Decimal{Unscaled: unscaled, Scale: scale})
+}
+
+// BindFloat32Vector binds v to vector<float, N> bind marker, length of v must be equal to its dimension.
+func (q *Query) BindFloat32Vector(pos int, v []float32) *Query {
+	return q.bind(pos, v)
+}
+
+// BindNull binds null to the bind marker at pos, writing null deletes the column value creating a tombstone.
+func (q *Query) BindNull(pos int) *Query {
+	return q.bind(pos, nil)
+}
+
+// BindUnset marks the bind marker at pos as unset, the column keeps its value as if it wasn't in the statement.
+// Use it instead of BindNull to avoid tombstones when inserting only some of the columns.
+func (q *Query) BindUnset(pos int) *Query {
+	if err := q.checkBounds(pos); err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
+	p := &q.stmt.Values[pos]
+	p.N, p.Bytes = unsetValueN, nil
+	return q
+}
+
+// unsetValueN is the length of the protocol v4 unset value.
+const unsetValueN = -2
+
+func (q *Query) bind(pos int, v any) *Query {
+	if err := q.checkBounds(pos); err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
+
+	if err := bindValue(q.session.cfg.CodecRegistry, &q.stmt.Values[pos], v); err != nil {
+		q.err = append(q.err, fmt.Errorf("bind %d: %w", pos, err))
+	}
+	return q
+}
+
+// BindName binds v to the bind marker with given name.
+// Names of prepared queries are resolved against the bind markers metadata, the name must be unique.
+// Unprepared queries send values together with their names and v is serialized with nil Option,
+// such queries can't be added to batches as protocol v4 doesn't support named values in BATCH.
+func (q *Query) BindName(name string, v Serializable) *Query {
+	p, err := q.namedValue(name)
+	if err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
+
+	p.N, p.Bytes, err = v.Serialize(p.Type)
+	if err != nil {
+		q.err = append(q.err, fmt.Errorf("bind %s: %w", name, err))
+	}
+	return q
+}
+
+// Named typed binders are the BindName counterparts of typed binders, see BindText and others.
+
+func (q *Query) BindNameText(name, v string) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameBlob(name string, v []byte) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameBool(name string, v bool) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameInt64(name string, v int64) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameInt32(name string, v int32) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameInt16(name string, v int16) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameInt8(name string, v int8) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameFloat32(name string, v float32) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameFloat64(name string, v float64) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameUUID(name string, v [16]byte) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameIP(name string, v net.IP) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameTimestamp(name string, v time.Time) *Query {
+	return q.bindName(name, frame.CqlFromTimestamp(v))
+}
+
+func (q *Query) BindNameDate(name string, v time.Time) *Query {
+	c, err := frame.CqlFromDate(v)
+	if err != nil {
+		q.err = append(q.err, fmt.Errorf("bind %s: %w", name, err))
+		return q
+	}
+	return q.bindName(name, c)
+}
+
+func (q *Query) BindNameTime(name string, v time.Duration) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameDuration(name string, v frame.Duration) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameVarint(name string, v *big.Int) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameDecimal(name string, unscaled *big.Int, scale int32) *Query {
+	return q.bindName(name, frame.Decimal{Unscaled: unscaled, Scale: scale})
+}
+
+func (q *Query) BindNameFloat32Vector(name string, v []float32) *Query {
+	return q.bindName(name, v)
+}
+
+func (q *Query) BindNameNull(name string) *Query {
+	return q.bindName(name, nil)
+}
+
+func (q *Query) BindNameUnset(name string) *Query {
+	p, err := q.namedValue(name)
+	if err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
+	p.N, p.Bytes = unsetValueN, nil
+	return q
+}
+
+func (q *Query) bindName(name string, v any) *Query {
+	p, err := q.namedValue(name)
+	if err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
+
+	if err := bindValue(q.session.cfg.CodecRegistry, p, v); err != nil {
+		q.err = append(q.err, fmt.Errorf("bind %s: %w", name, err))
+	}
+	return q
+}
+
+// namedValue returns value of the bind marker with given name,
+// for unprepared queries the value is added if the name wasn't bound yet.
+func (q *Query) namedValue(name string) (*frame.Value, error) {
+	if q.stmt.Metadata != nil {
+		pos := -1
+		for i := range q.stmt.BindMarkers {
+			if q.stmt.BindMarkers[i].Name != name {
+				continue
+			}
+			if pos != -1 {
+				return nil, fmt.Errorf("bind marker name %s is ambiguous, it's used at positions %d and %d", name, pos, i)
+			}
+			pos = i
+		}
+		if pos == -1 {
+			return nil, fmt.Errorf("no bind marker named %s", name)
+		}
+		return &q.stmt.Values[pos], nil
+	}
+
+	if len(q.stmt.Names) != len(q.stmt.Values) {
+		return nil, fmt.Errorf("can't bind %s, named and positional values can't be mixed", name)
+	}
+	for i := range q.stmt.Names {
+		if q.stmt.Names[i] == name {
+			return &q.stmt.Values[i], nil
+		}
+	}
+	q.stmt.Names = append(q.stmt.Names, name)
+	q.stmt.Values = append(q.stmt.Values, frame.Value{})
+	return &q.stmt.Values[len(q.stmt.Values)-1], nil
+}
+
+// bindValue encodes Go value v as the value of bind marker p, v must match the type of the marker.
+// Codecs of r take precedence over the built-in conversions. Nil values and nil pointers are encoded as null.
+func bindValue(r *CodecRegistry, p *frame.Value, v any) error {
+	if ok, err := r.encode(p, v); ok {
+		return err
+	}
+
+	var (
+		c   frame.CqlValue
+		err error
+	)
+	switch x := v.(type) {
+	case nil:
+		p.N, p.Bytes = -1, nil
+		return nil
+	case frame.CqlValue:
+		c = x
+	case Serializable:
+		p.N, p.Bytes, err = x.Serialize(p.Type)
+		return err
+	case string:
+		if markerType(p) == frame.ASCIIID {
+			c, err = frame.CqlFromASCII(x)
+		} else {
+			c, err = frame.CqlFromText(x)
+		}
+	case []byte:
+		if x == nil {
+			p.N, p.Bytes = -1, nil
+			return nil
+		}
+		c = frame.CqlFromBlob(x)
+	case bool:
+		c = frame.CqlFromBoolean(x)
+	case int8:
+		c = frame.CqlFromInt8(x)
+	case int16:
+		c = frame.CqlFromInt16(x)
+	case int32:
+		c = frame.CqlFromInt32(x)
+	case int64:
+		c = frame.CqlFromInt64(x)
+	case int:
+		c, err = cqlFromInt(x, markerType(p))
+	case float32:
+		c = frame.CqlFromFloat32(x)
+	case float64:
+		c = frame.CqlFromFloat64(x)
+	case [16]byte:
+		if markerType(p) == frame.TimeUUIDID {
+			c, err = frame.CqlFromTimeUUID(x)
+		} else {
+			c = frame.CqlFromUUID(x)
+		}
+	case net.IP:
+		c, err = frame.CqlFromIP(x)
+	case frame.Duration:
+		c, err = frame.CqlFromDuration(x)
+	case time.Time:
+		if markerType(p) == frame.DateID {
+			c, err = frame.CqlFromDate(x)
+		} else {
+			c = frame.CqlFromTimestamp(x)
+		}
+	case time.Duration:
+		c, err = frame.CqlFromTimeOfDay(x)
+	case *big.Int:
+		if x == nil {
+			p.N, p.Bytes = -1, nil
+			return nil
+		}
+		c = frame.CqlFromVarint(x)
+	case frame.Decimal:
+		c = frame.CqlFromDecimal(x)
+	case []float32:
+		if x == nil {
+			p.N, p.Bytes = -1, nil
+			return nil
+		}
+		if p.Type != nil && p.Type.Vector != nil && len(x) != p.Type.Vector.Dimension {
+			return fmt.Errorf("vector of %d elements doesn't match dimension %d", len(x), p.Type.Vector.Dimension)
+		}
+		c = frame.CqlFromFloat32Vector(x)
+	default:
+		return bindIndirect(r, p, v)
+	}
+	if err != nil {
+		return err
+	}
+	return setValue(p, c)
+}
+
+// cqlFromInt encodes v as the CQL integer type id, checking that it fits.
+func cqlFromInt(v int, id frame.OptionID) (frame.CqlValue, error) {
+	switch id {
+	case frame.TinyIntID:
+		if v < math.MinInt8 || v > math.MaxInt8 {
+			return frame.CqlValue{}, fmt.Errorf("%d overflows %v", v, id)
+		}
+		return frame.CqlFromInt8(int8(v)), nil
+	case frame.SmallIntID:
+		if v < math.MinInt16 || v > math.MaxInt16 {
+			return frame.CqlValue{}, fmt.Errorf("%d overflows %v", v, id)
+		}
+		return frame.CqlFromInt16(int16(v)), nil
+	case frame.IntID:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return frame.CqlValue{}, fmt.Errorf("%d overflows %v", v, id)
+		}
+		return frame.CqlFromInt32(int32(v)), nil
+	default:
+		return frame.CqlFromInt64(int64(v)), nil
+	}
+}
+
+// bindIndirect binds the value pointed to by v, nil pointers are bound as null.
+func bindIndirect(r *CodecRegistry, p *frame.Value, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return fmt.Errorf("can't bind %T to %v", v, markerType(p))
+	}
+	if rv.IsNil() {
+		p.N, p.Bytes = -1, nil
+		return nil
+	}
+	return bindValue(r, p, rv.Elem().Interface())
+}
+
+// markerType returns type of the bind marker, values of unprepared queries have no type
+// and are encoded as the natural CQL type of the Go value.
+func markerType(p *frame.Value) frame.OptionID {
+	if p.Type == nil {
+		return frame.CustomID
+	}
+	return p.Type.ID
+}
+
+// setValue sets p to c if c has the type of the bind marker, see frame.CqlValue.Serialize.
+func setValue(p *frame.Value, c frame.CqlValue) (err error) {
+	p.N, p.Bytes, err = c.Serialize(p.Type)
+	return err
+}
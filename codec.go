@@ -0,0 +1,140 @@
+package scylla
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+)
+
+// Codec encodes and decodes values of a Go type as values of a CQL type, see CodecRegistry.
+// Either function may be nil if values are only bound or only scanned.
+type Codec struct {
+	// Encode encodes v as a value of CQL type t, nil bytes are encoded as null.
+	Encode func(v any, t *frame.Option) ([]byte, error)
+	// Decode decodes non-null value v into dst, dst is a pointer to the Go type of the codec.
+	Decode func(v frame.CqlValue, dst any) error
+}
+
+// CodecRegistry maps pairs of Go types and CQL types to codecs, see SessionConfig.CodecRegistry.
+// Codecs are consulted before the built-in conversions when binding values and scanning results,
+// so they can override them or add support for custom CQL types.
+// It's safe for concurrent use, but codecs should be registered before the session is created.
+type CodecRegistry struct {
+	mu     sync.RWMutex
+	codecs map[codecKey]Codec
+}
+
+type codecKey struct {
+	goType reflect.Type
+	id     frame.OptionID
+	// custom is the class name of custom types.
+	custom string
+}
+
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{
+		codecs: make(map[codecKey]Codec),
+	}
+}
+
+func makeCodecKey(goType reflect.Type, t *frame.Option) codecKey {
+	k := codecKey{goType: goType, id: t.ID}
+	if t.ID == frame.CustomID && t.CustomOption != nil {
+		k.custom = t.CustomOption.Name
+	}
+	return k
+}
+
+// Register sets c as the codec of goType and CQL type t, replacing the previous one.
+// Custom types are matched by class name, other types by OptionID only,
+// e.g. a codec of list matches lists of any elements, the full type is passed to the codec.
+func (r *CodecRegistry) Register(goType reflect.Type, t frame.Option, c Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codecs[makeCodecKey(goType, &t)] = c
+}
+
+// RegisterCodec registers codec of Go type T and CQL type t built from typed functions, either of them may be nil.
+func RegisterCodec[T any](r *CodecRegistry, t frame.Option,
+	encode func(v T, t *frame.Option) ([]byte, error), decode func(v frame.CqlValue) (T, error)) {
+	var c Codec
+	if encode != nil {
+		c.Encode = func(v any, t *frame.Option) ([]byte, error) {
+			return encode(v.(T), t)
+		}
+	}
+	if decode != nil {
+		c.Decode = func(v frame.CqlValue, dst any) error {
+			x, err := decode(v)
+			if err != nil {
+				return err
+			}
+			*dst.(*T) = x
+			return nil
+		}
+	}
+	r.Register(reflect.TypeOf((*T)(nil)).Elem(), t, c)
+}
+
+func (r *CodecRegistry) lookup(goType reflect.Type, t *frame.Option) (Codec, bool) {
+	if r == nil || goType == nil || t == nil {
+		return Codec{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.codecs[makeCodecKey(goType, t)]
+	return c, ok
+}
+
+// hasGoType reports whether there is a codec of goType for any CQL type.
+func (r *CodecRegistry) hasGoType(goType reflect.Type) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for k := range r.codecs {
+		if k.goType == goType {
+			return true
+		}
+	}
+	return false
+}
+
+// encode encodes v as value of bind marker p if there is a codec for them.
+func (r *CodecRegistry) encode(p *frame.Value, v any) (ok bool, err error) {
+	c, ok := r.lookup(reflect.TypeOf(v), p.Type)
+	if !ok || c.Encode == nil {
+		return false, nil
+	}
+
+	b, err := c.Encode(v, p.Type)
+	if err != nil {
+		return true, fmt.Errorf("encode %T: %w", v, err)
+	}
+	if b == nil {
+		p.N, p.Bytes = -1, nil
+	} else {
+		p.N, p.Bytes = int32(len(b)), b
+	}
+	return true, nil
+}
+
+// decode decodes non-null v into dst if there is a codec for them.
+func (r *CodecRegistry) decode(v frame.CqlValue, dst any) (ok bool, err error) {
+	t := reflect.TypeOf(dst)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false, nil
+	}
+	c, ok := r.lookup(t.Elem(), v.Type)
+	if !ok || c.Decode == nil {
+		return false, nil
+	}
+
+	if err := c.Decode(v, dst); err != nil {
+		return true, fmt.Errorf("decode %v: %w", t.Elem(), err)
+	}
+	return true, nil
+}
@@ -0,0 +1,143 @@
+package scylla
+
+import (
+	"fmt"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/frame/response"
+	"go.uber.org/atomic"
+)
+
+type (
+	TopologyChangeEvent = response.TopologyChange
+	StatusChangeEvent   = response.StatusChange
+	SchemaChangeEvent   = response.SchemaChange
+)
+
+// Event is a cluster event, it's one of *TopologyChangeEvent, *StatusChangeEvent or *SchemaChangeEvent.
+type Event = frame.Response
+
+const defaultEventBufferSize = 256
+
+// Subscription receives cluster events of chosen types, see Session.Subscribe.
+type Subscription struct {
+	session *Session
+	types   map[EventType]bool
+	ch      chan Event
+	dropped atomic.Uint64
+}
+
+// Subscribe returns subscription to events of given types, or all events in SessionConfig.Events if no types are given.
+// Events are delivered after the driver has applied them to its topology, e.g. topology change events
+// after the topology is refreshed. Events that don't fit in the buffer of size SessionConfig.EventBufferSize
+// are dropped and counted, so that a slow subscriber doesn't stall the control connection.
+func (s *Session) Subscribe(types ...EventType) (*Subscription, error) {
+	if len(types) == 0 {
+		types = s.cfg.Events
+	}
+	sub := &Subscription{
+		session: s,
+		types:   make(map[EventType]bool, len(types)),
+	}
+	for _, t := range types {
+		if !s.registeredEvent(t) {
+			return nil, fmt.Errorf("can't subscribe to %s events, they are not in SessionConfig.Events", t)
+		}
+		sub.types[t] = true
+	}
+	size := s.cfg.EventBufferSize
+	if size <= 0 {
+		size = defaultEventBufferSize
+	}
+	sub.ch = make(chan Event, size)
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if s.Closed() {
+		close(sub.ch)
+		return sub, nil
+	}
+	if s.subs == nil {
+		s.subs = make(map[*Subscription]struct{})
+	}
+	s.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+func (s *Session) registeredEvent(t EventType) bool {
+	for _, e := range s.cfg.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Events returns channel of the subscription, it's closed by Close or when the session is closed.
+func (sub *Subscription) Events() <-chan Event {
+	return sub.ch
+}
+
+// Dropped returns the number of events dropped because the buffer was full, events dropped before reaching
+// any subscription are counted by Session.DroppedEvents.
+func (sub *Subscription) Dropped() uint64 {
+	return sub.dropped.Load()
+}
+
+// DroppedEvents returns the number of events dropped by the session before reaching subscriptions,
+// because too many of them were waiting for a topology refresh.
+func (s *Session) DroppedEvents() uint64 {
+	return s.cluster.DroppedEvents()
+}
+
+// Close stops delivering events and closes the events channel.
+func (sub *Subscription) Close() {
+	s := sub.session
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	if _, ok := s.subs[sub]; ok {
+		delete(s.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// publishEvent passes v to subscribers of its type without blocking, it's called by the cluster.
+func (s *Session) publishEvent(v frame.Response) {
+	var t EventType
+	switch v := v.(type) {
+	case *TopologyChangeEvent:
+		t = TopologyChange
+	case *StatusChangeEvent:
+		t = StatusChange
+	case *SchemaChangeEvent:
+		t = SchemaChange
+		if s.prepared != nil {
+			s.prepared.evict(v)
+		}
+	default:
+		return
+	}
+
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for sub := range s.subs {
+		if !sub.types[t] {
+			continue
+		}
+		select {
+		case sub.ch <- v:
+		default:
+			sub.dropped.Inc()
+		}
+	}
+}
+
+// closeSubscriptions closes events channels of all subscriptions.
+func (s *Session) closeSubscriptions() {
+	s.subsMu.Lock()
+	defer s.subsMu.Unlock()
+	for sub := range s.subs {
+		close(sub.ch)
+	}
+	s.subs = nil
+}
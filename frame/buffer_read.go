@@ -380,10 +380,16 @@ func (b *Buffer) ReadOption() Option {
 	id := OptionID(b.ReadShort())
 	switch id {
 	case CustomID:
-		return Option{
+		o := Option{
 			ID:           id,
 			CustomOption: b.ReadCustomOption(),
 		}
+		v, err := parseVectorOption(o.CustomOption.Name)
+		if err != nil && Debug {
+			log.Printf("parse vector type: %v", err)
+		}
+		o.Vector = v
+		return o
 	case ListID:
 		return Option{
 			ID:   id,
@@ -4,7 +4,9 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"math/big"
 	"net"
+	"time"
 	"unicode"
 	"unicode/utf8"
 )
@@ -307,6 +309,110 @@ func (c CqlValue) AsDuration() (Duration, error) {
 	return d, nil
 }
 
+// AsTimestamp decodes timestamp as time in UTC.
+func (c CqlValue) AsTimestamp() (time.Time, error) {
+	if c.Type.ID != TimestampID {
+		return time.Time{}, fmt.Errorf("%v is not of Timestamp type", c)
+	}
+
+	if len(c.Value) != 8 {
+		return time.Time{}, fmt.Errorf("expected 8 bytes, got %d", len(c.Value))
+	}
+
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(c.Value))).UTC(), nil
+}
+
+// AsDate decodes date as midnight of the day in UTC.
+func (c CqlValue) AsDate() (time.Time, error) {
+	if c.Type.ID != DateID {
+		return time.Time{}, fmt.Errorf("%v is not of Date type", c)
+	}
+
+	if len(c.Value) != 4 {
+		return time.Time{}, fmt.Errorf("expected 4 bytes, got %d", len(c.Value))
+	}
+
+	days := int64(binary.BigEndian.Uint32(c.Value)) - dateEpoch
+	return time.Unix(days*24*60*60, 0).UTC(), nil
+}
+
+// AsTime decodes either timestamp or date as time in UTC.
+func (c CqlValue) AsTime() (time.Time, error) {
+	switch c.Type.ID {
+	case TimestampID:
+		return c.AsTimestamp()
+	case DateID:
+		return c.AsDate()
+	default:
+		return time.Time{}, fmt.Errorf("%v is not of Timestamp or Date type", c)
+	}
+}
+
+// AsTimeOfDay decodes time as time since midnight.
+func (c CqlValue) AsTimeOfDay() (time.Duration, error) {
+	if c.Type.ID != TimeID {
+		return 0, fmt.Errorf("%v is not of Time type", c)
+	}
+
+	if len(c.Value) != 8 {
+		return 0, fmt.Errorf("expected 8 bytes, got %d", len(c.Value))
+	}
+
+	d := time.Duration(binary.BigEndian.Uint64(c.Value))
+	if d < 0 || d >= 24*time.Hour {
+		return 0, fmt.Errorf("time of day %v out of range", d)
+	}
+	return d, nil
+}
+
+func (c CqlValue) AsCounter() (int64, error) {
+	if c.Type.ID != CounterID {
+		return 0, fmt.Errorf("%v is not of Counter type", c)
+	}
+
+	if len(c.Value) != 8 {
+		return 0, fmt.Errorf("expected 8 bytes, got %d", len(c.Value))
+	}
+
+	return int64(binary.BigEndian.Uint64(c.Value)), nil
+}
+
+func (c CqlValue) AsVarint() (*big.Int, error) {
+	if c.Type.ID != VarintID {
+		return nil, fmt.Errorf("%v is not of Varint type", c)
+	}
+
+	if len(c.Value) == 0 {
+		return nil, fmt.Errorf("expected at least 1 byte, got 0")
+	}
+
+	return decodeVarint(c.Value), nil
+}
+
+func (c CqlValue) AsDecimal() (Decimal, error) {
+	if c.Type.ID != DecimalID {
+		return Decimal{}, fmt.Errorf("%v is not of Decimal type", c)
+	}
+
+	if len(c.Value) < 5 {
+		return Decimal{}, fmt.Errorf("expected at least 5 bytes, got %d", len(c.Value))
+	}
+
+	return Decimal{
+		Unscaled: decodeVarint(c.Value[4:]),
+		Scale:    int32(binary.BigEndian.Uint32(c.Value)),
+	}, nil
+}
+
+// decodeVarint decodes two's complement big-endian integer.
+func decodeVarint(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return v
+}
+
 func CqlFromASCII(s string) (CqlValue, error) {
 	for _, v := range s {
 		if v > unicode.MaxASCII {
@@ -415,7 +521,7 @@ func CqlFromTimeUUID(b [16]byte) (CqlValue, error) {
 }
 
 func CqlFromIP(ip net.IP) (CqlValue, error) {
-	if len(ip) != 4 || len(ip) != 16 {
+	if len(ip) != 4 && len(ip) != 16 {
 		return CqlValue{}, fmt.Errorf("invalid ip address")
 	}
 
@@ -475,3 +581,434 @@ func CqlFromDuration(d Duration) (CqlValue, error) {
 	c.Value = appendVInt(c.Value, d.Nanoseconds)
 	return c, nil
 }
+
+// CqlFromTimestamp encodes t as milliseconds since the Unix epoch, sub-millisecond precision is lost.
+func CqlFromTimestamp(t time.Time) CqlValue {
+	c := CqlFromInt64(t.UnixMilli())
+	c.Type.ID = TimestampID
+	return c
+}
+
+// dateEpoch is the date of the Unix epoch in date encoding, days are counted from 2^31.
+const dateEpoch = 1 << 31
+
+// CqlFromDate encodes the calendar date of t in its location, the time of day is ignored.
+func CqlFromDate(t time.Time) (CqlValue, error) {
+	y, m, d := t.Date()
+	days := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60)
+	if days < math.MinInt32 || days > math.MaxInt32 {
+		return CqlValue{}, fmt.Errorf("date %v out of range", t)
+	}
+	c := CqlFromInt32(int32(uint32(days + dateEpoch)))
+	c.Type.ID = DateID
+	return c, nil
+}
+
+// CqlFromTimeOfDay encodes time of day d as nanoseconds since midnight.
+func CqlFromTimeOfDay(d time.Duration) (CqlValue, error) {
+	if d < 0 || d >= 24*time.Hour {
+		return CqlValue{}, fmt.Errorf("time of day %v out of range", d)
+	}
+	c := CqlFromInt64(int64(d))
+	c.Type.ID = TimeID
+	return c, nil
+}
+
+func CqlFromCounter(v int64) CqlValue {
+	c := CqlFromInt64(v)
+	c.Type.ID = CounterID
+	return c
+}
+
+// CqlFromVarint encodes v in the shortest two's complement big-endian form.
+func CqlFromVarint(v *big.Int) CqlValue {
+	return CqlValue{
+		Type:  &Option{ID: VarintID},
+		Value: appendVarint(nil, v),
+	}
+}
+
+// CqlFromDecimal encodes d as its scale followed by the unscaled value as varint.
+func CqlFromDecimal(d Decimal) CqlValue {
+	c := CqlValue{
+		Type:  &Option{ID: DecimalID},
+		Value: make(Bytes, 4, 8),
+	}
+	binary.BigEndian.PutUint32(c.Value, uint32(d.Scale))
+	if d.Unscaled == nil {
+		c.Value = append(c.Value, 0)
+	} else {
+		c.Value = appendVarint(c.Value, d.Unscaled)
+	}
+	return c
+}
+
+func appendVarint(b []byte, v *big.Int) []byte {
+	switch v.Sign() {
+	case 0:
+		return append(b, 0)
+	case 1:
+		raw := v.Bytes()
+		if raw[0]&0x80 != 0 {
+			b = append(b, 0)
+		}
+		return append(b, raw...)
+	default:
+		// Bits of -v-1 are the inverted bits of v in two's complement.
+		t := new(big.Int).Neg(v)
+		raw := t.Sub(t, big.NewInt(1)).Bytes()
+		for i := range raw {
+			raw[i] = ^raw[i]
+		}
+		if len(raw) == 0 || raw[0]&0x80 == 0 {
+			b = append(b, 0xff)
+		}
+		return append(b, raw...)
+	}
+}
+
+// MapEntry is a key-value pair of a map, maps are decoded to slices of entries as CqlValues aren't comparable.
+type MapEntry struct {
+	Key   CqlValue
+	Value CqlValue
+}
+
+// AsList decodes elements of a list, their type is the element type of c.
+func (c CqlValue) AsList() ([]CqlValue, error) {
+	if c.Type.ID != ListID {
+		return nil, fmt.Errorf("%v is not of List type", c)
+	}
+	return decodeElements(c.Value, &c.Type.List.Element)
+}
+
+// AsSet decodes elements of a set, their type is the element type of c.
+func (c CqlValue) AsSet() ([]CqlValue, error) {
+	if c.Type.ID != SetID {
+		return nil, fmt.Errorf("%v is not of Set type", c)
+	}
+	return decodeElements(c.Value, &c.Type.Set.Element)
+}
+
+// AsMap decodes entries of a map in the order they were sent.
+func (c CqlValue) AsMap() ([]MapEntry, error) {
+	if c.Type.ID != MapID {
+		return nil, fmt.Errorf("%v is not of Map type", c)
+	}
+
+	v, err := decodeCollection(c.Value, 2)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]MapEntry, len(v)/2)
+	for i := range res {
+		res[i] = MapEntry{
+			Key:   CqlValue{Type: &c.Type.Map.Key, Value: v[2*i]},
+			Value: CqlValue{Type: &c.Type.Map.Value, Value: v[2*i+1]},
+		}
+	}
+	return res, nil
+}
+
+// AsTuple decodes elements of a tuple, null elements have nil Value.
+func (c CqlValue) AsTuple() ([]CqlValue, error) {
+	if c.Type.ID != TupleID {
+		return nil, fmt.Errorf("%v is not of Tuple type", c)
+	}
+
+	types := c.Type.Tuple.ValueTypes
+	res := make([]CqlValue, len(types))
+	raw := c.Value
+	for i := range types {
+		var err error
+		res[i].Type = &types[i]
+		if res[i].Value, raw, err = readValue(raw); err != nil {
+			return nil, err
+		}
+	}
+	if len(raw) != 0 {
+		return nil, fmt.Errorf("extra data after tuple value")
+	}
+	return res, nil
+}
+
+// AsUDT decodes fields of a user defined type by name, null fields have nil Value.
+// Fields added to the type after the value was written are null.
+func (c CqlValue) AsUDT() (map[string]CqlValue, error) {
+	if c.Type.ID != UDTID {
+		return nil, fmt.Errorf("%v is not of UDT type", c)
+	}
+
+	t := c.Type.UDT
+	res := make(map[string]CqlValue, len(t.FieldNames))
+	raw := c.Value
+	for i := range t.FieldNames {
+		v := CqlValue{Type: &t.FieldTypes[i]}
+		if len(raw) != 0 {
+			var err error
+			if v.Value, raw, err = readValue(raw); err != nil {
+				return nil, err
+			}
+		}
+		res[t.FieldNames[i]] = v
+	}
+	if len(raw) != 0 {
+		return nil, fmt.Errorf("extra data after UDT value")
+	}
+	return res, nil
+}
+
+func decodeElements(raw []byte, typ *Option) ([]CqlValue, error) {
+	v, err := decodeCollection(raw, 1)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]CqlValue, len(v))
+	for i := range v {
+		res[i] = CqlValue{Type: typ, Value: v[i]}
+	}
+	return res, nil
+}
+
+// decodeCollection decodes collection of n elements, each consisting of k values.
+func decodeCollection(raw []byte, k int) ([]Bytes, error) {
+	if len(raw) < 4 {
+		return nil, fmt.Errorf("expected at least 4 bytes, got %d", len(raw))
+	}
+	n := int64(int32(binary.BigEndian.Uint32(raw)))
+	raw = raw[4:]
+	// Each value takes at least 4 bytes, checked before allocating to reject malformed sizes.
+	if n < 0 || n*int64(k)*4 > int64(len(raw)) {
+		return nil, fmt.Errorf("invalid collection size %d", n)
+	}
+
+	res := make([]Bytes, int(n)*k)
+	for i := range res {
+		var err error
+		if res[i], raw, err = readValue(raw); err != nil {
+			return nil, err
+		}
+	}
+	if len(raw) != 0 {
+		return nil, fmt.Errorf("extra data after collection value")
+	}
+	return res, nil
+}
+
+// readValue reads [bytes] from raw, nil is returned for null values.
+func readValue(raw []byte) (v, rest Bytes, err error) {
+	if len(raw) < 4 {
+		return nil, nil, fmt.Errorf("expected at least 4 bytes, got %d", len(raw))
+	}
+	n := int32(binary.BigEndian.Uint32(raw))
+	raw = raw[4:]
+	if n < 0 {
+		return nil, raw, nil
+	}
+	if int(n) > len(raw) {
+		return nil, nil, fmt.Errorf("expected %d bytes, got %d", n, len(raw))
+	}
+	return raw[:n:n], raw[n:], nil
+}
+
+// CqlFromList encodes elements v of type elem as a list, elements can't be null.
+func CqlFromList(elem Option, v []CqlValue) (CqlValue, error) {
+	b, err := encodeElements(&elem, v)
+	if err != nil {
+		return CqlValue{}, err
+	}
+	return CqlValue{
+		Type:  &Option{ID: ListID, List: &ListOption{Element: elem}},
+		Value: b,
+	}, nil
+}
+
+// CqlFromSet encodes elements v of type elem as a set, elements can't be null.
+// Elements are sent in the given order, the nodes sort them and remove duplicates.
+func CqlFromSet(elem Option, v []CqlValue) (CqlValue, error) {
+	b, err := encodeElements(&elem, v)
+	if err != nil {
+		return CqlValue{}, err
+	}
+	return CqlValue{
+		Type:  &Option{ID: SetID, Set: &SetOption{Element: elem}},
+		Value: b,
+	}, nil
+}
+
+// CqlFromMap encodes entries v as a map with given key and value types, keys and values can't be null.
+func CqlFromMap(key, value Option, v []MapEntry) (CqlValue, error) {
+	b := make(Bytes, 4)
+	binary.BigEndian.PutUint32(b, uint32(len(v)))
+	for i := range v {
+		if err := checkElement(&key, v[i].Key); err != nil {
+			return CqlValue{}, fmt.Errorf("key %d: %w", i, err)
+		}
+		if err := checkElement(&value, v[i].Value); err != nil {
+			return CqlValue{}, fmt.Errorf("value %d: %w", i, err)
+		}
+		b = appendValue(b, v[i].Key.Value)
+		b = appendValue(b, v[i].Value.Value)
+	}
+	return CqlValue{
+		Type:  &Option{ID: MapID, Map: &MapOption{Key: key, Value: value}},
+		Value: b,
+	}, nil
+}
+
+// CqlFromTuple encodes elements v as a tuple of type t, elements with nil Value are null.
+func CqlFromTuple(t TupleOption, v []CqlValue) (CqlValue, error) {
+	if len(v) != len(t.ValueTypes) {
+		return CqlValue{}, fmt.Errorf("expected %d tuple elements, got %d", len(t.ValueTypes), len(v))
+	}
+
+	var b Bytes
+	for i := range v {
+		if v[i].Value != nil && !sameType(&t.ValueTypes[i], v[i].Type) {
+			return CqlValue{}, fmt.Errorf("element %d: %w", i, typeMismatch(&t.ValueTypes[i], v[i].Type))
+		}
+		b = appendValue(b, v[i].Value)
+	}
+	return CqlValue{
+		Type:  &Option{ID: TupleID, Tuple: &t},
+		Value: b,
+	}, nil
+}
+
+// CqlFromUDT encodes fields v as a value of user defined type t, fields missing in v are null.
+func CqlFromUDT(t UDTOption, v map[string]CqlValue) (CqlValue, error) {
+	var (
+		b Bytes
+		n int
+	)
+	for i, name := range t.FieldNames {
+		f, ok := v[name]
+		if ok {
+			n++
+		}
+		if f.Value != nil && !sameType(&t.FieldTypes[i], f.Type) {
+			return CqlValue{}, fmt.Errorf("field %s: %w", name, typeMismatch(&t.FieldTypes[i], f.Type))
+		}
+		b = appendValue(b, f.Value)
+	}
+	if n != len(v) {
+		for name := range v {
+			if !t.hasField(name) {
+				return CqlValue{}, fmt.Errorf("%s has no field %s", t.Name, name)
+			}
+		}
+	}
+	return CqlValue{
+		Type:  &Option{ID: UDTID, UDT: &t},
+		Value: b,
+	}, nil
+}
+
+func (t *UDTOption) hasField(name string) bool {
+	for i := range t.FieldNames {
+		if t.FieldNames[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeElements(typ *Option, v []CqlValue) (Bytes, error) {
+	b := make(Bytes, 4)
+	binary.BigEndian.PutUint32(b, uint32(len(v)))
+	for i := range v {
+		if err := checkElement(typ, v[i]); err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		b = appendValue(b, v[i].Value)
+	}
+	return b, nil
+}
+
+func checkElement(typ *Option, v CqlValue) error {
+	if v.Value == nil {
+		return fmt.Errorf("collection elements can't be null")
+	}
+	if !sameType(typ, v.Type) {
+		return typeMismatch(typ, v.Type)
+	}
+	return nil
+}
+
+func typeMismatch(expected, got *Option) error {
+	if got == nil {
+		return fmt.Errorf("expected %v, got untyped value", expected.ID)
+	}
+	return fmt.Errorf("expected %v, got %v", expected.ID, got.ID)
+}
+
+// appendValue appends v as [bytes], nil is appended as null.
+func appendValue(b, v Bytes) Bytes {
+	n := int32(len(v))
+	if v == nil {
+		n = -1
+	}
+	b = binary.BigEndian.AppendUint32(b, uint32(n))
+	return append(b, v...)
+}
+
+// sameType reports whether a and b describe the same type, names of user defined types aren't compared.
+func sameType(a, b *Option) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.ID != b.ID {
+		return false
+	}
+
+	switch a.ID {
+	case CustomID:
+		if a.Vector != nil && b.Vector != nil {
+			return a.Vector.Dimension == b.Vector.Dimension && sameType(&a.Vector.Element, &b.Vector.Element)
+		}
+		return a.CustomOption != nil && b.CustomOption != nil && a.CustomOption.Name == b.CustomOption.Name
+	case ListID:
+		return a.List != nil && b.List != nil && sameType(&a.List.Element, &b.List.Element)
+	case SetID:
+		return a.Set != nil && b.Set != nil && sameType(&a.Set.Element, &b.Set.Element)
+	case MapID:
+		return a.Map != nil && b.Map != nil && sameType(&a.Map.Key, &b.Map.Key) && sameType(&a.Map.Value, &b.Map.Value)
+	case TupleID:
+		return a.Tuple != nil && b.Tuple != nil && sameTypes(a.Tuple.ValueTypes, b.Tuple.ValueTypes)
+	case UDTID:
+		if a.UDT == nil || b.UDT == nil || len(a.UDT.FieldNames) != len(b.UDT.FieldNames) {
+			return false
+		}
+		for i := range a.UDT.FieldNames {
+			if a.UDT.FieldNames[i] != b.UDT.FieldNames[i] {
+				return false
+			}
+		}
+		return sameTypes(a.UDT.FieldTypes, b.UDT.FieldTypes)
+	default:
+		return true
+	}
+}
+
+func sameTypes(a, b []Option) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameType(&a[i], &b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Serialize returns value of c checking that it has type o, so that CqlValues can be bound as Serializable.
+// Bigint values can be bound to counters, type isn't checked if either o or type of c is nil.
+func (c CqlValue) Serialize(o *Option) (n int32, bytes []byte, err error) {
+	if o != nil && c.Type != nil && !sameType(o, c.Type) && (o.ID != CounterID || c.Type.ID != BigIntID) {
+		return 0, nil, fmt.Errorf("can't bind %v to %v", c.Type.ID, o.ID)
+	}
+	if c.Value == nil {
+		return -1, nil, nil
+	}
+	return int32(len(c.Value)), c.Value, nil
+}
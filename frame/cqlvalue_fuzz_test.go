@@ -2,8 +2,10 @@ package frame
 
 import (
 	"math"
+	"math/big"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -267,3 +269,197 @@ func FuzzCqlValueDuration(f *testing.F) {
 		}
 	})
 }
+
+func FuzzCqlValueTimestamp(f *testing.F) {
+	testCases := []int64{0, 1656070000123, -1656070000123, math.MinInt64, math.MaxInt64}
+	for _, tc := range testCases {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, data int64) {
+		in := CqlFromTimestamp(time.UnixMilli(data))
+		x, err := in.AsTimestamp()
+		if err != nil {
+			t.Errorf("cannot deserialize serialized data: %v", err)
+		}
+		out := CqlFromTimestamp(x)
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
+
+func FuzzCqlValueDate(f *testing.F) {
+	testCases := []uint32{0, 1 << 31, 1<<31 + 19167, 1<<31 - 19167, math.MaxUint32}
+	for _, tc := range testCases {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, data uint32) {
+		in := CqlValue{
+			Type:  &Option{ID: DateID},
+			Value: Bytes{byte(data >> 24), byte(data >> 16), byte(data >> 8), byte(data)},
+		}
+		x, err := in.AsDate()
+		if err != nil {
+			t.Errorf("cannot deserialize data: %v", err)
+		}
+		out, err := CqlFromDate(x)
+		if err != nil {
+			t.Errorf("cannot serialize deserialized data: %v", err)
+		}
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
+
+func FuzzCqlValueTimeOfDay(f *testing.F) {
+	testCases := []int64{0, 1, int64(12 * time.Hour), int64(24*time.Hour - 1)}
+	for _, tc := range testCases {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, data int64) {
+		in, err := CqlFromTimeOfDay(time.Duration(data))
+		if err != nil {
+			// Cannot serialize data, so we have no checks to do.
+			// This happens if data is not a time of day.
+			return
+		}
+		x, err := in.AsTimeOfDay()
+		if err != nil {
+			t.Errorf("cannot deserialize serialized data: %v", err)
+		}
+		out, err := CqlFromTimeOfDay(x)
+		if err != nil {
+			t.Errorf("cannot serialize deserialized data: %v", err)
+		}
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
+
+func FuzzCqlValueCounter(f *testing.F) {
+	testCases := []int64{0, 1, -1, math.MinInt64, math.MaxInt64}
+	for _, tc := range testCases {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, data int64) {
+		in := CqlFromCounter(data)
+		x, err := in.AsCounter()
+		if err != nil {
+			t.Errorf("cannot deserialize serialized data: %v", err)
+		}
+		out := CqlFromCounter(x)
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
+
+func FuzzCqlValueVarint(f *testing.F) {
+	testCases := [][]byte{{}, {0x7f}, {0x80}, {0xff, 0xff}, {0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}}
+	for _, tc := range testCases {
+		f.Add(tc, false)
+		f.Add(tc, true)
+	}
+	f.Fuzz(func(t *testing.T, data []byte, neg bool) {
+		v := new(big.Int).SetBytes(data)
+		if neg {
+			v.Neg(v)
+		}
+		in := CqlFromVarint(v)
+		x, err := in.AsVarint()
+		if err != nil {
+			t.Errorf("cannot deserialize serialized data: %v", err)
+		}
+		if x.Cmp(v) != 0 {
+			t.Errorf("in: %v, out: %v", v, x)
+		}
+		out := CqlFromVarint(x)
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
+
+func FuzzCqlValueDecimal(f *testing.F) {
+	f.Add([]byte{0x30, 0x39}, true, int32(2))
+	f.Add([]byte{}, false, int32(0))
+	f.Add([]byte{0x80}, false, int32(math.MinInt32))
+	f.Fuzz(func(t *testing.T, data []byte, neg bool, scale int32) {
+		v := Decimal{
+			Unscaled: new(big.Int).SetBytes(data),
+			Scale:    scale,
+		}
+		if neg {
+			v.Unscaled.Neg(v.Unscaled)
+		}
+		in := CqlFromDecimal(v)
+		x, err := in.AsDecimal()
+		if err != nil {
+			t.Errorf("cannot deserialize serialized data: %v", err)
+		}
+		if x.Scale != v.Scale || x.Unscaled.Cmp(v.Unscaled) != 0 {
+			t.Errorf("in: %v, out: %v", v, x)
+		}
+		out := CqlFromDecimal(x)
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
+
+func FuzzCqlValueList(f *testing.F) {
+	testCases := [][]byte{{0x00, 0x00, 0x00, 0x00}, {0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x01}}
+	for _, tc := range testCases {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := CqlValue{
+			Type:  &Option{ID: ListID, List: &ListOption{Element: Option{ID: BlobID}}},
+			Value: data,
+		}
+		x, err := in.AsList()
+		if err != nil {
+			// We skip tests with incorrect CqlValue.
+			t.Skip()
+		}
+		for i := range x {
+			if x[i].Value == nil {
+				// Null elements can't be serialized.
+				t.Skip()
+			}
+		}
+		out, err := CqlFromList(Option{ID: BlobID}, x)
+		if err != nil {
+			t.Errorf("cannot serialize deserialized data: %v", err)
+		}
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
+
+func FuzzCqlValueFloat32Vector(f *testing.F) {
+	testCases := [][]byte{{}, {0x3f, 0x80, 0x00, 0x00}, {0x7f, 0xc0, 0x00, 0x00, 0xff, 0x80, 0x00, 0x00}}
+	for _, tc := range testCases {
+		f.Add(tc)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data)%4 != 0 {
+			t.Skip()
+		}
+		in := CqlValue{
+			Type:  vectorType(Option{ID: FloatID}, len(data)/4),
+			Value: data,
+		}
+		x, err := in.AsFloat32Vector()
+		if err != nil {
+			t.Errorf("cannot deserialize data: %v", err)
+		}
+		out := CqlFromFloat32Vector(x)
+		if diff := cmp.Diff(in, out); diff != "" {
+			t.Errorf("in: %v, out: %v", in, out)
+		}
+	})
+}
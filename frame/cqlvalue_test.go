@@ -2,8 +2,10 @@ package frame
 
 import (
 	"math"
+	"math/big"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -1034,3 +1036,342 @@ func TestCqlValueAsDuration(t *testing.T) { // nolint:dupl // Tests are differen
 		})
 	}
 }
+
+func TestCqlFromVarint(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		value    int64
+		expected Bytes
+	}{
+		{name: "zero", value: 0, expected: Bytes{0x00}},
+		{name: "one", value: 1, expected: Bytes{0x01}},
+		{name: "127", value: 127, expected: Bytes{0x7f}},
+		{name: "128", value: 128, expected: Bytes{0x00, 0x80}},
+		{name: "minus one", value: -1, expected: Bytes{0xff}},
+		{name: "-128", value: -128, expected: Bytes{0x80}},
+		{name: "-129", value: -129, expected: Bytes{0xff, 0x7f}},
+		{name: "max int64", value: math.MaxInt64, expected: Bytes{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{name: "min int64", value: math.MinInt64, expected: Bytes{0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res := CqlFromVarint(big.NewInt(tc.value))
+			if diff := cmp.Diff(CqlValue{Type: &Option{ID: VarintID}, Value: tc.expected}, res); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestCqlFromDate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		value    time.Time
+		expected Bytes
+		valid    bool
+	}{
+		{
+			name:     "epoch",
+			value:    time.Unix(0, 0).UTC(),
+			expected: Bytes{0x80, 0x00, 0x00, 0x00},
+			valid:    true,
+		},
+		{
+			name:     "time of day is ignored",
+			value:    time.Date(1970, 1, 2, 23, 59, 59, 0, time.UTC),
+			expected: Bytes{0x80, 0x00, 0x00, 0x01},
+			valid:    true,
+		},
+		{
+			name:     "before epoch",
+			value:    time.Date(1969, 12, 31, 12, 0, 0, 0, time.UTC),
+			expected: Bytes{0x7f, 0xff, 0xff, 0xff},
+			valid:    true,
+		},
+		{
+			name:  "out of range",
+			value: time.Date(10_000_000, 1, 1, 0, 0, 0, 0, time.UTC),
+			valid: false,
+		},
+	}
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res, err := CqlFromDate(tc.value)
+			if err != nil {
+				if tc.valid {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !tc.valid {
+				t.Fatal("expected error, got nil")
+			}
+			if diff := cmp.Diff(CqlValue{Type: &Option{ID: DateID}, Value: tc.expected}, res); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestCqlFromDecimal(t *testing.T) {
+	t.Parallel()
+	res := CqlFromDecimal(Decimal{Unscaled: big.NewInt(-12345), Scale: 2})
+	expected := CqlValue{
+		Type:  &Option{ID: DecimalID},
+		Value: Bytes{0x00, 0x00, 0x00, 0x02, 0xcf, 0xc7},
+	}
+	if diff := cmp.Diff(expected, res); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestCqlValueAsTime(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		content  CqlValue
+		valid    bool
+		expected time.Time
+	}{
+		{
+			name: "timestamp",
+			content: CqlValue{
+				Type:  &Option{ID: TimestampID},
+				Value: Bytes{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0xe9},
+			},
+			valid:    true,
+			expected: time.Date(1970, 1, 1, 0, 0, 1, int(time.Millisecond), time.UTC),
+		},
+		{
+			name: "date",
+			content: CqlValue{
+				Type:  &Option{ID: DateID},
+				Value: Bytes{0x7f, 0xff, 0xff, 0xff},
+			},
+			valid:    true,
+			expected: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "wrong type",
+			content: CqlValue{
+				Type:  &Option{ID: TimeID},
+				Value: Bytes{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			},
+			valid: false,
+		},
+		{
+			name: "too short",
+			content: CqlValue{
+				Type:  &Option{ID: TimestampID},
+				Value: Bytes{0x00, 0x00, 0x00, 0x00},
+			},
+			valid: false,
+		},
+	}
+
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := tc.content.AsTime()
+			if err != nil {
+				if tc.valid {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !tc.valid {
+				t.Fatal("expected error, got nil")
+			}
+			if !v.Equal(tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, v)
+			}
+		})
+	}
+}
+
+func TestCqlValueAsVarint(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		content  Bytes
+		expected int64
+	}{
+		{name: "zero", content: Bytes{0x00}, expected: 0},
+		{name: "not minimal", content: Bytes{0x00, 0x00, 0x01}, expected: 1},
+		{name: "128", content: Bytes{0x00, 0x80}, expected: 128},
+		{name: "minus one", content: Bytes{0xff, 0xff}, expected: -1},
+		{name: "-129", content: Bytes{0xff, 0x7f}, expected: -129},
+	}
+
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := CqlValue{Type: &Option{ID: VarintID}, Value: tc.content}.AsVarint()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.Int64() != tc.expected {
+				t.Fatalf("expected %d, got %v", tc.expected, v)
+			}
+		})
+	}
+}
+
+func TestCqlValueNestedCollections(t *testing.T) {
+	t.Parallel()
+
+	// map<int, set<uuid>>
+	uuidSet := Option{ID: SetID, Set: &SetOption{Element: Option{ID: UUIDID}}}
+	s1, err := CqlFromSet(Option{ID: UUIDID}, []CqlValue{CqlFromUUID([16]byte{1}), CqlFromUUID([16]byte{2})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := CqlFromSet(Option{ID: UUIDID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := CqlFromMap(Option{ID: IntID}, uuidSet, []MapEntry{
+		{Key: CqlFromInt32(1), Value: s1},
+		{Key: CqlFromInt32(2), Value: s2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := m.AsMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if k, err := entries[0].Key.AsInt32(); err != nil || k != 1 {
+		t.Fatalf("expected key 1, got %d: %v", k, err)
+	}
+	elems, err := entries[0].Value.AsSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, err := elems[1].AsUUID(); err != nil || u != [16]byte{2} {
+		t.Fatalf("expected uuid 2, got %v: %v", u, err)
+	}
+	if elems, err := entries[1].Value.AsSet(); err != nil || len(elems) != 0 {
+		t.Fatalf("expected empty set, got %v: %v", elems, err)
+	}
+
+	// list<frozen<tuple<text, int>>>
+	tuple := TupleOption{ValueTypes: []Option{{ID: VarcharID}, {ID: IntID}}}
+	text, err := CqlFromText("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tv, err := CqlFromTuple(tuple, []CqlValue{text, {Type: &Option{ID: IntID}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := CqlFromList(Option{ID: TupleID, Tuple: &tuple}, []CqlValue{tv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := l.AsList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := list[0].AsTuple()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := values[0].AsText(); err != nil || s != "a" {
+		t.Fatalf("expected a, got %s: %v", s, err)
+	}
+	if values[1].Value != nil {
+		t.Fatalf("expected null, got %v", values[1].Value)
+	}
+
+	if _, err := CqlFromList(Option{ID: IntID}, []CqlValue{CqlFromInt64(1)}); err == nil {
+		t.Fatal("expected error for element of different type")
+	}
+	if _, err := CqlFromList(Option{ID: IntID}, []CqlValue{{Type: &Option{ID: IntID}}}); err == nil {
+		t.Fatal("expected error for null element")
+	}
+}
+
+func TestCqlValueAsUDT(t *testing.T) {
+	t.Parallel()
+	udt := UDTOption{
+		Keyspace:   "ks",
+		Name:       "address",
+		FieldNames: []string{"street", "number", "zip"},
+		FieldTypes: []Option{{ID: VarcharID}, {ID: IntID}, {ID: VarcharID}},
+	}
+	street, err := CqlFromText("Main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := CqlFromUDT(udt, map[string]CqlValue{"street": street, "number": CqlFromInt32(7)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := v.AsUDT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := fields["street"].AsText(); err != nil || s != "Main" {
+		t.Fatalf("expected Main, got %s: %v", s, err)
+	}
+	if n, err := fields["number"].AsInt32(); err != nil || n != 7 {
+		t.Fatalf("expected 7, got %d: %v", n, err)
+	}
+	if fields["zip"].Value != nil {
+		t.Fatalf("expected null zip, got %v", fields["zip"].Value)
+	}
+
+	// Value written before the zip field was added.
+	v.Value = v.Value[:len(v.Value)-4]
+	fields, err = v.AsUDT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["zip"].Value != nil {
+		t.Fatalf("expected null zip, got %v", fields["zip"].Value)
+	}
+
+	if _, err := CqlFromUDT(udt, map[string]CqlValue{"city": street}); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestCqlValueAsListMalformed(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name    string
+		content Bytes
+	}{
+		{name: "no size", content: Bytes{0x00, 0x00}},
+		{name: "negative size", content: Bytes{0xff, 0xff, 0xff, 0xff}},
+		{name: "size too big", content: Bytes{0x7f, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00}},
+		{name: "element too long", content: Bytes{0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x04, 0x00}},
+		{name: "extra data", content: Bytes{0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v := CqlValue{
+				Type:  &Option{ID: ListID, List: &ListOption{Element: Option{ID: IntID}}},
+				Value: tc.content,
+			}
+			if _, err := v.AsList(); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
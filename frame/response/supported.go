@@ -3,6 +3,7 @@ package response
 import (
 	"log"
 	"strconv"
+	"strings"
 
 	"github.com/kulezi/scylla-go-driver/frame"
 )
@@ -39,6 +40,12 @@ const (
 	ScyllaShardingIgnoreMSB = "SCYLLA_SHARDING_IGNORE_MSB"
 	ScyllaShardAwarePort    = "SCYLLA_SHARD_AWARE_PORT"
 	ScyllaShardAwarePortSSL = "SCYLLA_SHARD_AWARE_PORT_SSL"
+
+	// ScyllaLWTAddMetadataMark value has a form of ScyllaLWTOptMetaBitMask=<mask>, the same option has to be sent
+	// in STARTUP to make the node mark lightweight transactions with the mask in PREPARED metadata flags.
+	// https://github.com/kulezi/scylla/blob/4bfcead2ba60072c720241cce6f42f620930c380/docs/dev/protocol-extensions.md#lwt-prepared-statements-metadata-mark
+	ScyllaLWTAddMetadataMark = "SCYLLA_LWT_ADD_METADATA_MARK"
+	ScyllaLWTOptMetaBitMask  = "LWT_OPTIMIZATION_META_BIT_MASK"
 )
 
 func (s *Supported) ScyllaSupported() *ScyllaSupported {
@@ -91,6 +98,16 @@ func (s *Supported) ScyllaSupported() *ScyllaSupported {
 		}
 	}
 
+	if s, ok := s.Options[ScyllaLWTAddMetadataMark]; ok {
+		if mask, err := strconv.ParseUint(strings.TrimPrefix(s[0], ScyllaLWTOptMetaBitMask+"="), 10, 32); err != nil {
+			if frame.Debug {
+				log.Printf("scylla: failed to parse %s value %v: %s", ScyllaLWTAddMetadataMark, s, err)
+			}
+		} else {
+			si.LwtFlagMask = int(mask)
+		}
+	}
+
 	if s, ok := s.Options[ScyllaPartitioner]; ok {
 		si.Partitioner = s[0]
 	}
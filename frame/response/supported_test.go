@@ -65,13 +65,14 @@ func TestParseScyllaSupported(t *testing.T) {
 		{
 			name: "All options",
 			content: Supported{frame.StringMultiMap{
-				ScyllaShard:             []string{"3"},
-				ScyllaNrShards:          []string{"12"},
-				ScyllaShardingIgnoreMSB: []string{"22"},
-				ScyllaPartitioner:       []string{"org.apache.cassandra.dht.Murmur3Partitioner"},
-				ScyllaShardingAlgorithm: []string{"biased-token-round-robin"},
-				ScyllaShardAwarePort:    []string{"19042"},
-				ScyllaShardAwarePortSSL: []string{"19142"},
+				ScyllaShard:              []string{"3"},
+				ScyllaNrShards:           []string{"12"},
+				ScyllaShardingIgnoreMSB:  []string{"22"},
+				ScyllaPartitioner:        []string{"org.apache.cassandra.dht.Murmur3Partitioner"},
+				ScyllaShardingAlgorithm:  []string{"biased-token-round-robin"},
+				ScyllaShardAwarePort:     []string{"19042"},
+				ScyllaShardAwarePortSSL:  []string{"19142"},
+				ScyllaLWTAddMetadataMark: []string{"LWT_OPTIMIZATION_META_BIT_MASK=2147483648"},
 			}},
 			expected: ScyllaSupported{
 				Shard:             3,
@@ -81,6 +82,7 @@ func TestParseScyllaSupported(t *testing.T) {
 				ShardingAlgorithm: "biased-token-round-robin",
 				ShardAwarePort:    19042,
 				ShardAwarePortSSL: 19142,
+				LwtFlagMask:       2147483648,
 			},
 		},
 	}
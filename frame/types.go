@@ -2,6 +2,8 @@ package frame
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 	"net"
 )
 
@@ -360,6 +362,42 @@ const (
 	TupleID     OptionID = 0x0031
 )
 
+var optionIDNames = map[OptionID]string{
+	CustomID:    "custom",
+	ASCIIID:     "ascii",
+	BigIntID:    "bigint",
+	BlobID:      "blob",
+	BooleanID:   "boolean",
+	CounterID:   "counter",
+	DecimalID:   "decimal",
+	DoubleID:    "double",
+	FloatID:     "float",
+	IntID:       "int",
+	TimestampID: "timestamp",
+	UUIDID:      "uuid",
+	VarcharID:   "varchar",
+	VarintID:    "varint",
+	TimeUUIDID:  "timeuuid",
+	InetID:      "inet",
+	DateID:      "date",
+	TimeID:      "time",
+	SmallIntID:  "smallint",
+	TinyIntID:   "tinyint",
+	DurationID:  "duration",
+	ListID:      "list",
+	MapID:       "map",
+	SetID:       "set",
+	UDTID:       "udt",
+	TupleID:     "tuple",
+}
+
+func (id OptionID) String() string {
+	if name, ok := optionIDNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("OptionID(%#04x)", Short(id))
+}
+
 // https://github.com/apache/cassandra/blob/adcff3f630c0d07d1ba33bf23fcb11a6db1b9af1/doc/native_protocol_v4.spec#L612-L617
 type CustomOption struct {
 	Name string
@@ -394,6 +432,12 @@ type TupleOption struct {
 	ValueTypes []Option
 }
 
+// VectorOption describes Scylla vector<T, N> type, which is sent as a custom type.
+type VectorOption struct {
+	Element   Option
+	Dimension int
+}
+
 // https://github.com/apache/cassandra/blob/adcff3f630c0d07d1ba33bf23fcb11a6db1b9af1/doc/native_protocol_v4.spec#L236-L239
 type Option struct {
 	ID           OptionID
@@ -403,6 +447,8 @@ type Option struct {
 	Set          *SetOption
 	UDT          *UDTOption
 	Tuple        *TupleOption
+	// Vector is parsed from the class name of custom vector types.
+	Vector *VectorOption
 }
 
 // https://github.com/apache/cassandra/blob/adcff3f630c0d07d1ba33bf23fcb11a6db1b9af1/doc/native_protocol_v4.spec#L240
@@ -448,6 +494,13 @@ type Duration struct {
 	Nanoseconds int64
 }
 
+// Decimal is an arbitrary precision decimal number, its value is Unscaled * 10^-Scale.
+// Nil Unscaled is zero.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
 var errInvalidDuration = errors.New("duration fields must be all positive or all negative")
 
 // validate checks that the Duration complies with the protocol specification.
@@ -0,0 +1,220 @@
+package frame
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Vectors are sent as custom types with class name such as
+// org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.FloatType, 768).
+const (
+	marshalPrefix   = "org.apache.cassandra.db.marshal."
+	vectorClassName = marshalPrefix + "VectorType"
+)
+
+// marshalTypes maps class names of native types to their IDs.
+var marshalTypes = map[string]OptionID{
+	"AsciiType":         ASCIIID,
+	"LongType":          BigIntID,
+	"BytesType":         BlobID,
+	"BooleanType":       BooleanID,
+	"CounterColumnType": CounterID,
+	"DecimalType":       DecimalID,
+	"DoubleType":        DoubleID,
+	"FloatType":         FloatID,
+	"Int32Type":         IntID,
+	"TimestampType":     TimestampID,
+	"UUIDType":          UUIDID,
+	"UTF8Type":          VarcharID,
+	"IntegerType":       VarintID,
+	"TimeUUIDType":      TimeUUIDID,
+	"InetAddressType":   InetID,
+	"SimpleDateType":    DateID,
+	"TimeType":          TimeID,
+	"ShortType":         SmallIntID,
+	"ByteType":          TinyIntID,
+	"DurationType":      DurationID,
+}
+
+// parseVectorOption parses class name of a vector type, nil is returned if name isn't a vector.
+func parseVectorOption(name string) (*VectorOption, error) {
+	if !strings.HasPrefix(name, vectorClassName+"(") || !strings.HasSuffix(name, ")") {
+		return nil, nil
+	}
+
+	params := name[len(vectorClassName)+1 : len(name)-1]
+	i := strings.LastIndexByte(params, ',')
+	if i == -1 {
+		return nil, fmt.Errorf("invalid vector type %s", name)
+	}
+	dim, err := strconv.Atoi(strings.TrimSpace(params[i+1:]))
+	if err != nil || dim <= 0 {
+		return nil, fmt.Errorf("invalid vector dimension in %s", name)
+	}
+	elem, err := parseMarshalType(strings.TrimSpace(params[:i]))
+	if err != nil {
+		return nil, err
+	}
+	return &VectorOption{
+		Element:   elem,
+		Dimension: dim,
+	}, nil
+}
+
+// parseMarshalType parses class name of a vector element, types other than native types and vectors are kept as custom.
+func parseMarshalType(name string) (Option, error) {
+	if id, ok := marshalTypes[strings.TrimPrefix(name, marshalPrefix)]; ok {
+		return Option{ID: id}, nil
+	}
+
+	o := Option{
+		ID:           CustomID,
+		CustomOption: &CustomOption{Name: name},
+	}
+	v, err := parseVectorOption(name)
+	if err != nil {
+		return Option{}, err
+	}
+	o.Vector = v
+	return o, nil
+}
+
+// fixedSize returns size of values of type o, or 0 if the size is variable.
+// Elements of fixed size are serialized in vectors without lengths.
+func (o *Option) fixedSize() int {
+	switch o.ID {
+	case BooleanID:
+		return 1
+	case FloatID, IntID, DateID:
+		return 4
+	case BigIntID, DoubleID, TimestampID, TimeID:
+		return 8
+	case UUIDID, TimeUUIDID:
+		return 16
+	case CustomID:
+		if o.Vector != nil {
+			if n := o.Vector.Element.fixedSize(); n > 0 {
+				return n * o.Vector.Dimension
+			}
+		}
+	}
+	return 0
+}
+
+// AsVector decodes elements of a vector, their type is the element type of c.
+func (c CqlValue) AsVector() ([]CqlValue, error) {
+	if c.Type.Vector == nil {
+		return nil, fmt.Errorf("%v is not of Vector type", c)
+	}
+
+	t := c.Type.Vector
+	res := make([]CqlValue, t.Dimension)
+	raw := c.Value
+	size := t.Element.fixedSize()
+	if size > 0 && len(raw) != size*t.Dimension {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size*t.Dimension, len(raw))
+	}
+	for i := range res {
+		n := size
+		if n == 0 {
+			l, k, err := decodeUnsignedVInt(raw)
+			if err != nil {
+				return nil, err
+			}
+			raw = raw[k:]
+			if l > uint64(len(raw)) {
+				return nil, fmt.Errorf("expected %d bytes, got %d", l, len(raw))
+			}
+			n = int(l)
+		}
+		res[i] = CqlValue{
+			Type:  &t.Element,
+			Value: raw[:n:n],
+		}
+		raw = raw[n:]
+	}
+	if len(raw) != 0 {
+		return nil, fmt.Errorf("extra data after vector value")
+	}
+	return res, nil
+}
+
+// AsFloat32Vector decodes vector<float, N>.
+func (c CqlValue) AsFloat32Vector() ([]float32, error) {
+	if c.Type.Vector == nil || c.Type.Vector.Element.ID != FloatID {
+		return nil, fmt.Errorf("%v is not of Vector<Float> type", c)
+	}
+
+	dim := c.Type.Vector.Dimension
+	if len(c.Value) != 4*dim {
+		return nil, fmt.Errorf("expected %d bytes, got %d", 4*dim, len(c.Value))
+	}
+	res := make([]float32, dim)
+	for i := range res {
+		res[i] = math.Float32frombits(binary.BigEndian.Uint32(c.Value[4*i:]))
+	}
+	return res, nil
+}
+
+// CqlFromVector encodes elements v as a vector of elements of type elem, the dimension is the length of v.
+// Elements can't be null.
+func CqlFromVector(elem Option, v []CqlValue) (CqlValue, error) {
+	size := elem.fixedSize()
+	var b Bytes
+	if size > 0 {
+		b = make(Bytes, 0, size*len(v))
+	}
+	for i := range v {
+		if err := checkElement(&elem, v[i]); err != nil {
+			return CqlValue{}, fmt.Errorf("element %d: %w", i, err)
+		}
+		if size == 0 {
+			b = appendUnsignedVInt(b, uint64(len(v[i].Value)))
+		} else if len(v[i].Value) != size {
+			return CqlValue{}, fmt.Errorf("element %d: expected %d bytes, got %d", i, size, len(v[i].Value))
+		}
+		b = append(b, v[i].Value...)
+	}
+	return CqlValue{
+		Type:  vectorType(elem, len(v)),
+		Value: b,
+	}, nil
+}
+
+// CqlFromFloat32Vector encodes v as vector<float, len(v)>.
+func CqlFromFloat32Vector(v []float32) CqlValue {
+	b := make(Bytes, 4*len(v))
+	for i := range v {
+		binary.BigEndian.PutUint32(b[4*i:], math.Float32bits(v[i]))
+	}
+	return CqlValue{
+		Type:  vectorType(Option{ID: FloatID}, len(v)),
+		Value: b,
+	}
+}
+
+func vectorType(elem Option, dim int) *Option {
+	return &Option{
+		ID:           CustomID,
+		CustomOption: &CustomOption{Name: fmt.Sprintf("%s(%s, %d)", vectorClassName, marshalClassName(&elem), dim)},
+		Vector: &VectorOption{
+			Element:   elem,
+			Dimension: dim,
+		},
+	}
+}
+
+func marshalClassName(o *Option) string {
+	if o.ID == CustomID && o.CustomOption != nil {
+		return o.CustomOption.Name
+	}
+	for name, id := range marshalTypes {
+		if id == o.ID {
+			return marshalPrefix + name
+		}
+	}
+	return o.ID.String()
+}
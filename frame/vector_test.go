@@ -0,0 +1,128 @@
+package frame
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBufferReadOptionVector(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name     string
+		class    string
+		expected *VectorOption
+	}{
+		{
+			name:  "float vector",
+			class: "org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.FloatType, 768)",
+			expected: &VectorOption{
+				Element:   Option{ID: FloatID},
+				Dimension: 768,
+			},
+		},
+		{
+			name: "vector of vectors",
+			class: "org.apache.cassandra.db.marshal.VectorType(" +
+				"org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.UTF8Type, 2), 3)",
+			expected: &VectorOption{
+				Element: Option{
+					ID: CustomID,
+					CustomOption: &CustomOption{
+						Name: "org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.UTF8Type, 2)",
+					},
+					Vector: &VectorOption{
+						Element:   Option{ID: VarcharID},
+						Dimension: 2,
+					},
+				},
+				Dimension: 3,
+			},
+		},
+		{
+			name:  "other custom type",
+			class: "com.example.MoneyType",
+		},
+		{
+			name:  "invalid dimension",
+			class: "org.apache.cassandra.db.marshal.VectorType(org.apache.cassandra.db.marshal.FloatType, x)",
+		},
+	}
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var buf Buffer
+			buf.WriteShort(Short(CustomID))
+			buf.WriteString(tc.class)
+			o := buf.ReadOption()
+			if err := buf.Error(); err != nil {
+				t.Fatal(err)
+			}
+			expected := Option{
+				ID:           CustomID,
+				CustomOption: &CustomOption{Name: tc.class},
+				Vector:       tc.expected,
+			}
+			if diff := cmp.Diff(expected, o); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestCqlValueAsVector(t *testing.T) {
+	t.Parallel()
+
+	floats := []float32{1, -2.5, 3}
+	v := CqlFromFloat32Vector(floats)
+	if v.Type.Vector == nil || v.Type.Vector.Dimension != 3 {
+		t.Fatalf("expected vector of dimension 3, got %+v", v.Type)
+	}
+	out, err := v.AsFloat32Vector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(floats, out); diff != "" {
+		t.Fatal(diff)
+	}
+	elems, err := v.AsVector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f, err := elems[1].AsFloat32(); err != nil || f != -2.5 {
+		t.Fatalf("expected -2.5, got %v: %v", f, err)
+	}
+
+	// Elements of variable size are prefixed with their length.
+	a, err := CqlFromText("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc, err := CqlFromText("bc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = CqlFromVector(Option{ID: VarcharID}, []CqlValue{a, bc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(Bytes{0x01, 'a', 0x02, 'b', 'c'}, v.Value); diff != "" {
+		t.Fatal(diff)
+	}
+	elems, err = v.AsVector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := elems[1].AsText(); err != nil || s != "bc" {
+		t.Fatalf("expected bc, got %s: %v", s, err)
+	}
+
+	v.Value = v.Value[:4]
+	if _, err := v.AsVector(); err == nil {
+		t.Fatal("expected error for truncated vector")
+	}
+	if _, err := CqlFromVector(Option{ID: FloatID}, []CqlValue{CqlFromFloat64(1)}); err == nil {
+		t.Fatal("expected error for element of different type")
+	}
+}
@@ -9,6 +9,16 @@ import (
 
 // decodeVInt decodes [vint] into an int64 value and returns the length on the wire it has read.
 func decodeVInt(data []byte) (value int64, length int, err error) {
+	uvalue, length, err := decodeUnsignedVInt(data)
+	if err != nil {
+		return 0, 0, err
+	}
+	value = int64((uvalue >> 1) ^ -(uvalue & 1))
+	return
+}
+
+// decodeUnsignedVInt decodes unsigned [vint], without zigzag encoding of the sign.
+func decodeUnsignedVInt(data []byte) (uvalue uint64, length int, err error) {
 	if len(data) == 0 {
 		return 0, 0, fmt.Errorf("decode vint: not enough bytes")
 	}
@@ -17,7 +27,6 @@ func decodeVInt(data []byte) (value int64, length int, err error) {
 	if len(additional) < additionalBytes {
 		return 0, 0, fmt.Errorf("decode vint: not enough bytes")
 	}
-	var uvalue uint64
 	// copy the first byte, clearing the leading 1 bits
 	uvalue = uint64(data[0]) & (uint64(0xff) >> additionalBytes)
 	// copy the additional bytes
@@ -25,16 +34,19 @@ func decodeVInt(data []byte) (value int64, length int, err error) {
 		uvalue = (uvalue << 8) | (uint64(additional[i]))
 	}
 	length = 1 + additionalBytes
-	value = int64((uvalue >> 1) ^ -(uvalue & 1))
 	return
 }
 
 // appendVInt encodes value as [vint] and appends it to appendTo.
 func appendVInt(appendTo []byte, value int64) []byte {
-	if value == 0 {
+	return appendUnsignedVInt(appendTo, uint64((value>>63)^(value<<1)))
+}
+
+// appendUnsignedVInt encodes uvalue as unsigned [vint] and appends it to appendTo.
+func appendUnsignedVInt(appendTo []byte, uvalue uint64) []byte {
+	if uvalue == 0 {
 		return append(appendTo, 0)
 	}
-	uvalue := uint64((value >> 63) ^ (value << 1))
 	var data [9]byte
 	i := 8
 	for i > 0 && uvalue > 0 {
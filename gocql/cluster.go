@@ -213,7 +213,7 @@ type ClusterConfig struct {
 }
 
 func NewCluster(hosts ...string) *ClusterConfig {
-	cfg := ClusterConfig{Hosts: hosts, WriteCoalesceWaitTime: 200 * time.Microsecond}
+	cfg := ClusterConfig{Hosts: hosts, WriteCoalesceWaitTime: 200 * time.Microsecond, DefaultTimestamp: true}
 	return &cfg
 }
 
@@ -221,6 +221,12 @@ func sessionConfigFromGocql(cfg *ClusterConfig) (scylla.SessionConfig, error) {
 	scfg := scylla.DefaultSessionConfig(cfg.Keyspace, cfg.Hosts...)
 	scfg.Hosts = cfg.Hosts
 	scfg.WriteCoalesceWaitTime = cfg.WriteCoalesceWaitTime
+	if cfg.Timeout > 0 {
+		scfg.RequestTimeout = cfg.Timeout
+	}
+	if !cfg.DefaultTimestamp {
+		scfg.TimestampGenerator = nil
+	}
 	if _, ok := cfg.Compressor.(SnappyCompressor); ok {
 		scfg.Compression = scylla.Snappy
 	}
@@ -1,6 +1,6 @@
 module github.com/gocql/gocql
 
-go 1.18
+go 1.23
 
 require (
 	github.com/kulezi/scylla-go-driver v0.1.6
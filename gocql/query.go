@@ -111,7 +111,8 @@ func (q *Query) Consistency(c Consistency) *Query {
 
 // CustomPayload sets the custom payload level for this query.
 func (q *Query) CustomPayload(customPayload map[string][]byte) *Query {
-	panic("unimplemented")
+	q.query.SetCustomPayload(customPayload)
+	return q
 }
 
 // Trace enables tracing of this query. Look at the documentation of the
@@ -132,11 +133,13 @@ func (q *Query) PageSize(n int) *Query {
 }
 
 func (q *Query) DefaultTimestamp(enable bool) *Query {
-	panic("unimplemented")
+	q.query.SetDefaultTimestamp(enable)
+	return q
 }
 
 func (q *Query) WithTimestamp(timestamp int64) *Query {
-	panic("unimplemented")
+	q.query.SetTimestamp(timestamp)
+	return q
 }
 
 func (q *Query) RoutingKey(routingKey []byte) *Query {
@@ -153,7 +156,8 @@ func (q *Query) RetryPolicy(r RetryPolicy) *Query {
 }
 
 func (q *Query) SetSpeculativeExecutionPolicy(sp SpeculativeExecutionPolicy) *Query {
-	panic("unimplemented")
+	q.query.SetSpeculativeExecutionPolicy(speculativeExecutionPolicy(sp))
+	return q
 }
 
 func (q *Query) Idempotent(value bool) *Query {
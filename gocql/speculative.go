@@ -0,0 +1,40 @@
+package gocql
+
+import (
+	"time"
+
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+// SimpleSpeculativeExecution starts up to NumAttempts additional executions, each TimeoutDelay after the previous one.
+type SimpleSpeculativeExecution struct {
+	NumAttempts  int
+	TimeoutDelay time.Duration
+}
+
+func (sp *SimpleSpeculativeExecution) Attempts() int        { return sp.NumAttempts }
+func (sp *SimpleSpeculativeExecution) Delay() time.Duration { return sp.TimeoutDelay }
+
+// NonSpeculativeExecution disables speculative execution.
+type NonSpeculativeExecution struct{}
+
+func (sp NonSpeculativeExecution) Attempts() int        { return 0 }
+func (sp NonSpeculativeExecution) Delay() time.Duration { return 1 }
+
+// speculativeExecutionPolicy converts gocql policy to the one used by the driver, nil means no speculative execution.
+func speculativeExecutionPolicy(sp SpeculativeExecutionPolicy) transport.SpeculativeExecutionPolicy {
+	switch v := sp.(type) {
+	case transport.SpeculativeExecutionPolicy:
+		return v
+	case interface {
+		Attempts() int
+		Delay() time.Duration
+	}:
+		if v.Attempts() <= 0 {
+			return nil
+		}
+		return transport.NewConstantSpeculativeExecutionPolicy(v.Delay(), v.Attempts())
+	default:
+		return nil
+	}
+}
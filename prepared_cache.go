@@ -0,0 +1,175 @@
+package scylla
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/transport"
+	"go.uber.org/atomic"
+)
+
+type preparedCacheKey struct {
+	keyspace string
+	content  string
+}
+
+type preparedCacheEntry struct {
+	key  preparedCacheKey
+	stmt transport.Statement
+}
+
+// preparedCache is a LRU cache of statements prepared in the session.
+type preparedCache struct {
+	size int
+	ll   *list.List
+	m    map[preparedCacheKey]*list.Element
+	mu   sync.Mutex // mu guards ll and m
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newPreparedCache(size int) *preparedCache {
+	return &preparedCache{
+		size: size,
+		ll:   list.New(),
+		m:    make(map[preparedCacheKey]*list.Element),
+	}
+}
+
+func (c *preparedCache) get(k preparedCacheKey) (transport.Statement, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.m[k]
+	if !ok {
+		c.misses.Inc()
+		return transport.Statement{}, false
+	}
+
+	c.hits.Inc()
+	c.ll.MoveToFront(e)
+	return e.Value.(*preparedCacheEntry).stmt, true
+}
+
+func (c *preparedCache) put(k preparedCacheKey, stmt transport.Statement) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.m[k]; ok {
+		e.Value.(*preparedCacheEntry).stmt = stmt
+		c.ll.MoveToFront(e)
+		return
+	}
+
+	c.m[k] = c.ll.PushFront(&preparedCacheEntry{key: k, stmt: stmt})
+	for c.ll.Len() > c.size {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		delete(c.m, e.Value.(*preparedCacheEntry).key)
+	}
+}
+
+// evict removes statements which schema change v could have invalidated, so that they are prepared anew.
+func (c *preparedCache) evict(v *SchemaChangeEvent) {
+	// New schema elements don't change already prepared statements.
+	if v.Change == frame.Created {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for e := c.ll.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*preparedCacheEntry); affectedBy(&entry.stmt, v) {
+			c.ll.Remove(e)
+			delete(c.m, entry.key)
+		}
+		e = next
+	}
+}
+
+// affectedBy reports whether stmt uses schema element changed by v, tables of the statement are known from
+// its bind markers and result columns. Statements without them could use any table, so they are always affected.
+// Changes of types, functions and aggregates affect all statements in the keyspace.
+func affectedBy(stmt *transport.Statement, v *SchemaChangeEvent) bool {
+	cols := stmt.BindMarkers
+	if len(cols) == 0 && stmt.Metadata != nil {
+		cols = stmt.Metadata.Columns
+	}
+	if len(cols) == 0 {
+		return true
+	}
+	if cols[0].Keyspace != v.Keyspace {
+		return false
+	}
+	if v.Target != frame.Table {
+		return true
+	}
+	return cols[0].Table == v.Object
+}
+
+func (c *preparedCache) statements() []transport.Statement {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res := make([]transport.Statement, 0, c.ll.Len())
+	for e := c.ll.Front(); e != nil; e = e.Next() {
+		res = append(res, e.Value.(*preparedCacheEntry).stmt)
+	}
+	return res
+}
+
+func (c *preparedCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// prepareOnNode prepares all cached statements on a node that came up or joined the cluster,
+// so that executing them there doesn't fail with UNPREPARED.
+func (c *preparedCache) prepareOnNode(ctx context.Context, n *transport.Node, logger transport.Logger) {
+	for _, stmt := range c.statements() {
+		if _, err := n.Prepare(ctx, stmt); err != nil {
+			logger.Printf("session: failed to prepare cached statement %q on %v: %v", stmt.Content, n, err)
+		}
+	}
+}
+
+// PreparedCacheStats describes usage of the session's prepared statements cache.
+type PreparedCacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+func (s *Session) PreparedCacheStats() PreparedCacheStats {
+	if s.prepared == nil {
+		return PreparedCacheStats{}
+	}
+
+	return PreparedCacheStats{
+		Hits:   s.prepared.hits.Load(),
+		Misses: s.prepared.misses.Load(),
+		Size:   s.prepared.len(),
+	}
+}
+
+// withPrepared returns stmt filled with the result of preparing it, values are allocated anew for binding.
+func withPrepared(stmt, p transport.Statement) transport.Statement {
+	stmt.ID = p.ID
+	stmt.PkIndexes = p.PkIndexes
+	stmt.PkCnt = p.PkCnt
+	stmt.BindMarkers = p.BindMarkers
+	stmt.Keyspace = p.Keyspace
+	stmt.Metadata = p.Metadata
+	stmt.LWT = p.LWT
+	stmt.Values = make([]frame.Value, len(p.Values))
+	for i := range p.Values {
+		stmt.Values[i].Type = p.Values[i].Type
+	}
+	return stmt
+}
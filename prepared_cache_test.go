@@ -0,0 +1,123 @@
+package scylla
+
+import (
+	"testing"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+func preparedStmt(content, keyspace, table string) transport.Statement {
+	return transport.Statement{
+		ID:          frame.Bytes(content),
+		Content:     content,
+		BindMarkers: []frame.ColumnSpec{{Keyspace: keyspace, Table: table, Name: "pk"}},
+	}
+}
+
+func TestPreparedCacheLRU(t *testing.T) {
+	t.Parallel()
+
+	key := func(content string) preparedCacheKey { return preparedCacheKey{keyspace: "ks", content: content} }
+	c := newPreparedCache(2)
+	c.put(key("a"), preparedStmt("a", "ks", "t"))
+	c.put(key("b"), preparedStmt("b", "ks", "t"))
+
+	// Statements of other keyspaces are cached separately.
+	if _, ok := c.get(preparedCacheKey{content: "a"}); ok {
+		t.Fatal("expected miss for statement prepared without keyspace")
+	}
+	// Reading a makes b the least recently used statement.
+	if _, ok := c.get(key("a")); !ok {
+		t.Fatal("expected hit for a")
+	}
+	c.put(key("c"), preparedStmt("c", "ks", "t"))
+	if _, ok := c.get(key("b")); ok {
+		t.Fatal("expected b to be evicted")
+	}
+
+	// Putting a cached statement again replaces it without evicting others.
+	c.put(key("a"), preparedStmt("a", "ks", "t2"))
+	if c.len() != 2 {
+		t.Fatalf("expected 2 statements in cache, got %d", c.len())
+	}
+	stmt, ok := c.get(key("a"))
+	if !ok || stmt.BindMarkers[0].Table != "t2" {
+		t.Fatalf("expected updated statement a, got %+v", stmt)
+	}
+	if _, ok := c.get(key("c")); !ok {
+		t.Fatal("expected hit for c")
+	}
+
+	if h, m := c.hits.Load(), c.misses.Load(); h != 3 || m != 2 {
+		t.Fatalf("expected 3 hits and 2 misses, got %d and %d", h, m)
+	}
+}
+
+func TestPreparedCacheEvict(t *testing.T) {
+	t.Parallel()
+
+	stmts := []transport.Statement{
+		preparedStmt("ks1.t1", "ks1", "t1"),
+		preparedStmt("ks1.t2", "ks1", "t2"),
+		preparedStmt("ks2.t1", "ks2", "t1"),
+		{ID: frame.Bytes("ks1.t3"), Content: "ks1.t3", Metadata: &frame.ResultMetadata{
+			Columns: []frame.ColumnSpec{{Keyspace: "ks1", Table: "t3", Name: "v"}},
+		}},
+		{ID: frame.Bytes("unknown"), Content: "unknown"},
+	}
+
+	testCases := []struct {
+		name     string
+		event    SchemaChangeEvent
+		expected []string
+	}{
+		{
+			name:     "created table",
+			event:    SchemaChangeEvent{Change: frame.Created, Target: frame.Table, Keyspace: "ks1", Object: "t1"},
+			expected: []string{"ks1.t1", "ks1.t2", "ks2.t1", "ks1.t3", "unknown"},
+		},
+		{
+			name:     "updated table",
+			event:    SchemaChangeEvent{Change: frame.Updated, Target: frame.Table, Keyspace: "ks1", Object: "t1"},
+			expected: []string{"ks1.t2", "ks2.t1", "ks1.t3"},
+		},
+		{
+			name:     "dropped table from result metadata",
+			event:    SchemaChangeEvent{Change: frame.Dropped, Target: frame.Table, Keyspace: "ks1", Object: "t3"},
+			expected: []string{"ks1.t1", "ks1.t2", "ks2.t1"},
+		},
+		{
+			name:     "updated type",
+			event:    SchemaChangeEvent{Change: frame.Updated, Target: frame.UserType, Keyspace: "ks1", Object: "udt"},
+			expected: []string{"ks2.t1"},
+		},
+		{
+			name:     "dropped keyspace",
+			event:    SchemaChangeEvent{Change: frame.Dropped, Target: frame.Keyspace, Keyspace: "ks2"},
+			expected: []string{"ks1.t1", "ks1.t2", "ks1.t3"},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := newPreparedCache(len(stmts))
+			for _, stmt := range stmts {
+				c.put(preparedCacheKey{content: stmt.Content}, stmt)
+			}
+
+			c.evict(&tc.event)
+
+			for _, content := range tc.expected {
+				if _, ok := c.get(preparedCacheKey{content: content}); !ok {
+					t.Errorf("expected %q to stay in cache", content)
+				}
+			}
+			if c.len() != len(tc.expected) {
+				t.Fatalf("expected %d statements in cache, got %d", len(tc.expected), c.len())
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/kulezi/scylla-go-driver/frame"
 	"github.com/kulezi/scylla-go-driver/transport"
@@ -15,7 +16,9 @@ type Query struct {
 	buf       frame.Buffer
 	exec      func(context.Context, *transport.Conn, transport.Statement, frame.Bytes) (transport.QueryResult, error)
 	asyncExec func(context.Context, *transport.Conn, transport.Statement, frame.Bytes, transport.ResponseHandler)
-	res       []transport.ResponseHandler
+	res       []asyncResult
+	spec      transport.SpeculativeExecutionPolicy
+	tsGen     TimestampGenerator
 
 	pageState []byte
 	err       []error
@@ -43,81 +46,153 @@ func (q *Query) Exec(ctx context.Context) (Result, error) {
 		return Result{}, err
 	}
 
-	// Most queries don't need retries, rd will be allocated on first failure.
-	var rd transport.RetryDecider
-	var lastErr error
-	n := q.session.cfg.HostSelectionPolicy.Node(info, 0)
-	i := 0
-	for n != nil {
-	sameNodeRetries:
-		for {
-			conn, err := n.Conn(info)
-			if err != nil {
-				lastErr = err
-				break sameNodeRetries
-			}
+	stmt := q.stmt
+	stmt.Timestamp = q.timestamp()
+	res, err := q.session.execute(ctx, info, stmt.Idempotent, stmt.Consistency, q.spec,
+		q.execution(&stmt))
+	if q.stmt.ID != nil {
+		copyPrepared(&q.stmt, &stmt)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	q.session.handleWarnings(q.stmt.Content, &res)
+
+	return q.session.result(res), q.session.handleAutoAwaitSchemaAgreement(ctx, q.stmt.Content, &res)
+}
+
+// execution returns execution of stmt, speculative executions execute clones of stmt.
+func (q *Query) execution(stmt *transport.Statement) execution {
+	return execution{
+		exec: func(ctx context.Context, conn *transport.Conn) (transport.QueryResult, error) {
+			return q.exec(ctx, conn, *stmt, nil)
+		},
+		prepared: []*transport.Statement{stmt},
+		clone: func() execution {
+			c := stmt.Clone()
+			return q.execution(&c)
+		},
+	}
+}
 
-			res, err := q.exec(ctx, conn, q.stmt, nil)
-			if err != nil {
-				ri := transport.RetryInfo{
-					Error:       err,
-					Idempotent:  q.stmt.Idempotent,
-					Consistency: q.stmt.Consistency,
-				}
+// ExecCAS executes a lightweight transaction and reports whether it was applied.
+// If it wasn't, previous holds the existing values of the row without the [applied] column.
+// Serial phase of the transaction uses consistency set by SetSerialConsistency,
+// as lightweight transactions aren't idempotent the statement is retried on a different node only
+// if the coordinator rejected it without executing, i.e. on unavailable and bootstrapping errors.
+func (q *Query) ExecCAS(ctx context.Context) (applied bool, previous frame.Row, err error) {
+	if q.err != nil {
+		return false, nil, fmt.Errorf("query can't be executed: %v", q.err)
+	}
 
-				if rd == nil {
-					rd = q.session.cfg.RetryPolicy.NewRetryDecider()
-				}
-				switch rd.Decide(ri) {
-				case transport.RetrySameNode:
-					continue sameNodeRetries
-				case transport.RetryNextNode:
-					lastErr = err
-					break sameNodeRetries
-				case transport.DontRetry:
-					return Result{}, err
-				}
-			}
+	info, err := q.info()
+	if err != nil {
+		return false, nil, err
+	}
+
+	// Prepared metadata of conditional statements doesn't describe the result, so we need it in the response.
+	stmt := q.stmt
+	stmt.NoSkipMetadata = true
+	stmt.Timestamp = q.timestamp()
+	res, err := q.session.execute(ctx, info, false, stmt.Consistency, nil,
+		q.execution(&stmt))
+	if q.stmt.ID != nil {
+		copyPrepared(&q.stmt, &stmt)
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	q.session.handleWarnings(q.stmt.Content, &res)
+
+	applied, rows, err := casResult(res)
+	if err != nil || len(rows) == 0 {
+		return applied, nil, err
+	}
+	return applied, rows[0], nil
+}
+
+const appliedColumn = "[applied]"
 
-			return Result(res), q.session.handleAutoAwaitSchemaAgreement(ctx, q.stmt.Content, &res)
+// casResult parses the result of a conditional statement, rows are returned only if it wasn't applied.
+func casResult(res transport.QueryResult) (bool, []frame.Row, error) {
+	idx := -1
+	for i := range res.ColSpec {
+		if res.ColSpec[i].Name == appliedColumn {
+			idx = i
+			break
 		}
+	}
+	if idx == -1 || len(res.Rows) == 0 {
+		return false, nil, fmt.Errorf("result doesn't contain %s column, is it a conditional statement?", appliedColumn)
+	}
 
-		i++
-		n = q.session.cfg.HostSelectionPolicy.Node(info, i)
+	v := res.Rows[0][idx]
+	if len(v.Value) != 1 {
+		return false, nil, fmt.Errorf("%s column: expected 1 byte, got %d", appliedColumn, len(v.Value))
+	}
+	applied, err := v.AsBoolean()
+	if err != nil {
+		return false, nil, fmt.Errorf("%s column: %w", appliedColumn, err)
+	}
+	if applied {
+		return true, nil, nil
 	}
 
-	if lastErr == nil {
-		return Result{}, fmt.Errorf("no connection to execute the query on")
+	rows := make([]frame.Row, len(res.Rows))
+	for i, r := range res.Rows {
+		rows[i] = make(frame.Row, 0, len(r)-1)
+		rows[i] = append(rows[i], r[:idx]...)
+		rows[i] = append(rows[i], r[idx+1:]...)
 	}
-	return Result{}, lastErr
+	return false, rows, nil
 }
 
-func (q *Query) pickConn(qi transport.QueryInfo) (*transport.Conn, error) {
+func (q *Query) pickConn(qi transport.QueryInfo) (*transport.Node, *transport.Conn, error) {
 	n := q.session.cfg.HostSelectionPolicy.Node(qi, 0)
 
 	conn, err := n.Conn(qi)
 	if err != nil {
-		return nil, errNoConnection
+		return nil, nil, errNoConnection
 	}
 
-	return conn, nil
+	return n, conn, nil
+}
+
+// asyncResult holds everything needed to replay an asynchronous request if the node didn't know the statement.
+type asyncResult struct {
+	h         transport.ResponseHandler
+	node      *transport.Node
+	conn      *transport.Conn
+	stmt      transport.Statement
+	pageState []byte
+
+	ctx context.Context // nolint:containedctx // Fetch has to replay the request in the context it was sent in.
 }
 
 func (q *Query) AsyncExec(ctx context.Context) {
 	stmt := q.stmt.Clone()
+	stmt.Timestamp = q.timestamp()
 	info, err := q.info()
 	if err != nil {
-		q.res = append(q.res, transport.MakeResponseHandlerWithError(err))
+		q.res = append(q.res, asyncResult{h: transport.MakeResponseHandlerWithError(err)})
+		return
 	}
 
-	conn, err := q.pickConn(info)
+	n, conn, err := q.pickConn(info)
 	if err != nil {
-		q.res = append(q.res, transport.MakeResponseHandlerWithError(err))
+		q.res = append(q.res, asyncResult{h: transport.MakeResponseHandlerWithError(err)})
 		return
 	}
 
 	h := transport.MakeResponseHandler()
-	q.res = append(q.res, h)
+	q.res = append(q.res, asyncResult{
+		h:         h,
+		node:      n,
+		conn:      conn,
+		stmt:      stmt,
+		pageState: q.pageState,
+		ctx:       ctx,
+	})
 	q.asyncExec(ctx, conn, stmt, q.pageState, h)
 }
 
@@ -129,50 +204,72 @@ func (q *Query) Fetch() (Result, error) {
 		return Result{}, ErrNoQueryResults
 	}
 
-	h := q.res[0]
+	r := q.res[0]
 	q.res = q.res[1:]
 
-	resp := <-h
+	resp := <-r.h
 	if resp.Err != nil {
 		return Result{}, resp.Err
 	}
 
-	res, err := transport.MakeQueryResult(resp.Response, q.stmt.Metadata)
-	return Result(res), err
+	res, err := transport.MakeQueryResult(resp, q.stmt.Metadata)
+	if err != nil && r.node != nil {
+		ok, rerr := q.session.reprepare(r.ctx, r.node, err, &r.stmt)
+		if rerr != nil {
+			err = rerr
+		} else if ok {
+			copyPrepared(&q.stmt, &r.stmt)
+			res, err = q.exec(r.ctx, r.conn, r.stmt, r.pageState)
+		}
+	}
+	if err == nil {
+		q.session.handleWarnings(q.stmt.Content, &res)
+	}
+	return q.session.result(res), err
 }
 
-// https://github.com/kulezi/scylla/blob/40adf38915b6d8f5314c621a94d694d172360833/compound_compat.hh#L33-L47
 func (q *Query) token() (transport.Token, bool) {
-	if q.stmt.PkCnt == 0 {
+	return statementToken(&q.buf, &q.stmt)
+}
+
+// statementToken computes the partition token of a statement from its bound partition key values,
+// buf is used as a scratch space for composite partition keys.
+// https://github.com/kulezi/scylla/blob/40adf38915b6d8f5314c621a94d694d172360833/compound_compat.hh#L33-L47
+func statementToken(buf *frame.Buffer, stmt *transport.Statement) (transport.Token, bool) {
+	if stmt.PkCnt == 0 {
 		return 0, false
 	}
 
-	q.buf.Reset()
-	if q.stmt.PkCnt == 1 {
-		return transport.MurmurToken(q.stmt.Values[q.stmt.PkIndexes[0]].Bytes), true
+	buf.Reset()
+	if stmt.PkCnt == 1 {
+		return transport.MurmurToken(stmt.Values[stmt.PkIndexes[0]].Bytes), true
 	}
-	for _, idx := range q.stmt.PkIndexes {
-		size := q.stmt.Values[idx].N
-		q.buf.WriteShort(frame.Short(size))
-		q.buf.Write(q.stmt.Values[idx].Bytes)
-		q.buf.WriteByte(0)
+	for _, idx := range stmt.PkIndexes {
+		size := stmt.Values[idx].N
+		buf.WriteShort(frame.Short(size))
+		buf.Write(stmt.Values[idx].Bytes)
+		buf.WriteByte(0)
 	}
 
-	return transport.MurmurToken(q.buf.Bytes()), true
+	return transport.MurmurToken(buf.Bytes()), true
 }
 
 func (q *Query) info() (transport.QueryInfo, error) {
 	token, tokenAware := q.token()
+	if tokenAware && q.stmt.LWT {
+		return q.session.cluster.NewLWTQueryInfo(token, q.stmt.Keyspace)
+	}
 	if tokenAware {
-		// TODO: Will the driver support using different keyspaces than default?
-		info, err := q.session.cluster.NewTokenAwareQueryInfo(token, "")
-		return info, err
+		return q.session.cluster.NewTokenAwareQueryInfo(token, q.stmt.Keyspace)
 	}
 
 	return q.session.cluster.NewQueryInfo(), nil
 }
 
 func (q *Query) checkBounds(pos int) error {
+	if q.stmt.Names != nil {
+		return fmt.Errorf("can't bind position %d, named and positional values can't be mixed", pos)
+	}
 	if q.stmt.Metadata != nil {
 		if pos < 0 || pos >= len(q.stmt.Values) {
 			return fmt.Errorf("no bind marker with position %d", pos)
@@ -231,9 +328,18 @@ func (q *Query) Bind(pos int, v Serializable) *Query {
 	return q
 }
 
+// BindInt64 binds v to bigint or counter bind marker at pos, reusing the buffer of the previous value.
 func (q *Query) BindInt64(pos int, v int64) *Query {
+	if err := q.checkBounds(pos); err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
 	p := &q.stmt.Values[pos]
-	if p.N == 0 {
+	if id := markerType(p); id != frame.CustomID && id != frame.BigIntID && id != frame.CounterID {
+		q.err = append(q.err, fmt.Errorf("bind %d: can't bind %v to %v", pos, frame.BigIntID, id))
+		return q
+	}
+	if p.N != 8 {
 		p.N = 8
 		p.Bytes = make([]byte, 8)
 	}
@@ -282,6 +388,64 @@ func (q *Query) Compression() bool {
 	return q.stmt.Compression
 }
 
+// SetTimeout limits time of waiting for the response to each request sent by the query,
+// overriding the session's RequestTimeout. It doesn't apply to AsyncExec.
+func (q *Query) SetTimeout(v time.Duration) {
+	q.stmt.Timeout = v
+}
+
+func (q *Query) Timeout() time.Duration {
+	return q.stmt.Timeout
+}
+
+// SetTracing enables tracing of the query, ID of the trace is returned in Result.TracingID
+// and the trace can be fetched with Session.FetchTrace.
+func (q *Query) SetTracing(v bool) {
+	q.stmt.Tracing = v
+}
+
+func (q *Query) Tracing() bool {
+	return q.stmt.Tracing
+}
+
+// SetCustomPayload sets the custom payload sent with each request of the query,
+// payload returned by the server is available in Result.CustomPayload. The map mustn't be modified afterwards.
+func (q *Query) SetCustomPayload(v map[string][]byte) {
+	q.stmt.CustomPayload = v
+}
+
+func (q *Query) CustomPayload() map[string][]byte {
+	return q.stmt.CustomPayload
+}
+
+// SetTimestamp sets the default timestamp of the query in microseconds, overriding the session's TimestampGenerator.
+// If v is 0, the timestamp is generated again on each execution.
+func (q *Query) SetTimestamp(v int64) {
+	q.stmt.Timestamp = v
+}
+
+func (q *Query) Timestamp() int64 {
+	return q.stmt.Timestamp
+}
+
+// SetDefaultTimestamp controls whether the session's TimestampGenerator is used for the query,
+// if it's disabled and no timestamp is set the coordinator assigns it.
+func (q *Query) SetDefaultTimestamp(v bool) {
+	if v {
+		q.tsGen = q.session.cfg.TimestampGenerator
+	} else {
+		q.tsGen = nil
+	}
+}
+
+// timestamp returns timestamp set by SetTimestamp or a new one from the generator.
+func (q *Query) timestamp() frame.Long {
+	if q.stmt.Timestamp == 0 && q.tsGen != nil {
+		return q.tsGen.Next()
+	}
+	return q.stmt.Timestamp
+}
+
 func (q *Query) SetIdempotent(v bool) {
 	q.stmt.Idempotent = v
 }
@@ -290,15 +454,32 @@ func (q *Query) Idempotent() bool {
 	return q.stmt.Idempotent
 }
 
+// SetSpeculativeExecutionPolicy overrides the session's speculative execution policy for this query,
+// nil disables speculative execution. It only applies to idempotent queries.
+func (q *Query) SetSpeculativeExecutionPolicy(v transport.SpeculativeExecutionPolicy) {
+	q.spec = v
+}
+
+func (q *Query) SpeculativeExecutionPolicy() transport.SpeculativeExecutionPolicy {
+	return q.spec
+}
+
 func (q *Query) NoSkipMetadata() *Query {
 	q.stmt.NoSkipMetadata = true
 	return q
 }
 
-type Result transport.QueryResult
+// Result of a query, the codecs of the session that executed it are used by Scan.
+type Result struct {
+	transport.QueryResult
+	codecs *CodecRegistry
+}
 
 func (q *Query) Iter(ctx context.Context) Iter {
+	// Canceled by Close to stop the worker and abandon the in-flight page request.
+	ctx, cancel := context.WithCancel(ctx)
 	stmt := q.stmt.Clone()
+	stmt.Timestamp = q.timestamp()
 
 	var pageState []byte
 	if q.pageState != nil {
@@ -311,7 +492,11 @@ func (q *Query) Iter(ctx context.Context) Iter {
 		nextCh:    make(chan transport.QueryResult),
 		errCh:     make(chan error, 1),
 
+		cancel: cancel,
+
 		meta: stmt.Metadata,
+
+		codecs: q.session.cfg.CodecRegistry,
 	}
 
 	info, err := q.info()
@@ -326,6 +511,9 @@ func (q *Query) Iter(ctx context.Context) Iter {
 		queryInfo: info,
 		pickNode:  q.session.cfg.HostSelectionPolicy.Node,
 		queryExec: q.exec,
+		reprepare: q.session.reprepare,
+
+		warningHandler: q.session.cfg.WarningHandler,
 
 		requestCh: it.requestCh,
 		nextCh:    it.nextCh,
@@ -348,9 +536,15 @@ type Iter struct {
 	nextCh    chan transport.QueryResult
 	errCh     chan error
 	closed    bool
+	cancel    context.CancelFunc
 
 	meta *frame.ResultMetadata
 	err  error
+
+	// plan is used by ScanStruct.
+	plan *structPlan
+
+	codecs *CodecRegistry
 }
 
 var (
@@ -358,26 +552,6 @@ var (
 	ErrNoMoreRows = fmt.Errorf("no more rows left")
 )
 
-// func (it *Iter) Scan(dst ...interface{}) bool {
-// 	row, err := it.Next()
-// 	if err != nil {
-// 		return false
-// 	}
-
-// 	if it.meta == nil || len(it.meta.Columns) != len(row) {
-// 		it.err = fmt.Errorf("column count mismatch, expected %d, got %d", len(it.meta.Columns), len(row))
-// 	}
-
-// 	for i := range row {
-// 		if err := Unmarshal(&it.meta.Columns[i].Type, row[i].Value, dst[i]); err != nil {
-// 			it.err = err
-// 			return false
-// 		}
-// 	}
-
-// 	return true
-// }
-
 func (it *Iter) Next() (frame.Row, error) {
 	if it.closed {
 		return nil, nil
@@ -415,11 +589,19 @@ func (it *Iter) Close() error {
 	}
 	it.closed = true
 	close(it.requestCh)
+	it.cancel()
 	return it.err
 }
 
 func (it *Iter) Columns() []frame.ColumnSpec {
-	return it.meta.Columns
+	// Columns of results follow the statement if it was prepared again after a schema change.
+	if it.result.ColSpec != nil {
+		return it.result.ColSpec
+	}
+	if it.meta != nil {
+		return it.meta.Columns
+	}
+	return nil
 }
 
 func (it *Iter) NumRows() int {
@@ -438,10 +620,14 @@ type iterWorker struct {
 	queryInfo transport.QueryInfo
 	pickNode  func(transport.QueryInfo, int) *transport.Node
 	nodeIdx   int
+	node      *transport.Node
 	conn      *transport.Conn
 	connErr   error
 
-	rd transport.RetryDecider
+	rd        transport.RetryDecider
+	reprepare func(context.Context, *transport.Node, error, ...*transport.Statement) (bool, error)
+
+	warningHandler WarningHandler
 
 	requestCh chan struct{}
 	nextCh    chan transport.QueryResult
@@ -454,6 +640,7 @@ func (w *iterWorker) loop(ctx context.Context) {
 		w.errCh <- fmt.Errorf("can't pick a node to execute request")
 		return
 	}
+	w.node = n
 	w.conn, w.connErr = n.Conn(w.queryInfo)
 
 	for {
@@ -467,9 +654,17 @@ func (w *iterWorker) loop(ctx context.Context) {
 			w.errCh <- err
 			return
 		}
+		if w.warningHandler != nil && len(res.Warnings) != 0 {
+			w.warningHandler(w.stmt.Content, res.Warnings)
+		}
 
 		w.pagingState = res.PagingState
-		w.nextCh <- res
+		select {
+		case w.nextCh <- res:
+		case <-ctx.Done():
+			// Iter was closed before the page was consumed.
+			return
+		}
 		if !res.HasMorePages {
 			w.errCh <- ErrNoMoreRows
 			return
@@ -481,6 +676,7 @@ func (w *iterWorker) exec(ctx context.Context) (transport.QueryResult, error) {
 	w.rd.Reset()
 	var lastErr error
 	for {
+		reprepared := false
 	sameNodeRetries:
 		for {
 			if w.connErr != nil {
@@ -489,6 +685,18 @@ func (w *iterWorker) exec(ctx context.Context) (transport.QueryResult, error) {
 			}
 			res, err := w.queryExec(ctx, w.conn, w.stmt, w.pagingState)
 			if err != nil {
+				// Replaying the request after preparing the statement again isn't counted as a retry.
+				if !reprepared {
+					ok, rerr := w.reprepare(ctx, w.node, err, &w.stmt)
+					if rerr != nil {
+						return transport.QueryResult{}, rerr
+					}
+					if ok {
+						reprepared = true
+						continue sameNodeRetries
+					}
+				}
+
 				ri := transport.RetryInfo{
 					Error:       err,
 					Idempotent:  w.stmt.Idempotent,
@@ -518,6 +726,7 @@ func (w *iterWorker) exec(ctx context.Context) (transport.QueryResult, error) {
 			return transport.QueryResult{}, lastErr
 		}
 
+		w.node = n
 		w.conn, w.connErr = n.Conn(w.queryInfo)
 	}
 }
@@ -0,0 +1,88 @@
+package scylla
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+func TestCasResult(t *testing.T) {
+	t.Parallel()
+
+	applied := func(v bool) frame.CqlValue { return frame.CqlFromBoolean(v) }
+	pk, v := frame.CqlFromInt32(1), frame.CqlFromInt32(2)
+	cols := []frame.ColumnSpec{{Name: appliedColumn}, {Name: "pk"}, {Name: "v"}}
+
+	testCases := []struct {
+		name     string
+		res      transport.QueryResult
+		applied  bool
+		rows     []frame.Row
+		hasError bool
+	}{
+		{
+			name:    "applied",
+			res:     transport.QueryResult{ColSpec: cols[:1], Rows: []frame.Row{{applied(true)}}},
+			applied: true,
+		},
+		{
+			name: "not applied",
+			res:  transport.QueryResult{ColSpec: cols, Rows: []frame.Row{{applied(false), pk, v}}},
+			rows: []frame.Row{{pk, v}},
+		},
+		{
+			name: "not applied in batch",
+			res: transport.QueryResult{
+				ColSpec: cols,
+				Rows:    []frame.Row{{applied(false), pk, v}, {applied(false), v, pk}},
+			},
+			rows: []frame.Row{{pk, v}, {v, pk}},
+		},
+		{
+			name: "applied column not first",
+			res:  transport.QueryResult{ColSpec: []frame.ColumnSpec{{Name: "pk"}, {Name: appliedColumn}}, Rows: []frame.Row{{pk, applied(false)}}},
+			rows: []frame.Row{{pk}},
+		},
+		{
+			name:     "no applied column",
+			res:      transport.QueryResult{ColSpec: cols[1:], Rows: []frame.Row{{pk, v}}},
+			hasError: true,
+		},
+		{
+			name:     "no rows",
+			res:      transport.QueryResult{ColSpec: cols[:1]},
+			hasError: true,
+		},
+		{
+			name:     "null applied",
+			res:      transport.QueryResult{ColSpec: cols[:1], Rows: []frame.Row{{frame.CqlValue{}}}},
+			hasError: true,
+		},
+		{
+			name:     "applied of wrong type",
+			res:      transport.QueryResult{ColSpec: cols[:1], Rows: []frame.Row{{frame.CqlValue{Type: pk.Type, Value: []byte{1}}}}},
+			hasError: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			applied, rows, err := casResult(tc.res)
+			if (err != nil) != tc.hasError {
+				t.Fatalf("expected error: %v, got %v", tc.hasError, err)
+			}
+			if applied != tc.applied {
+				t.Fatalf("expected applied %v, got %v", tc.applied, applied)
+			}
+			if !slices.EqualFunc(rows, tc.rows, func(a, b frame.Row) bool {
+				return slices.EqualFunc(a, b, func(x, y frame.CqlValue) bool { return slices.Equal(x.Value, y.Value) })
+			}) {
+				t.Fatalf("expected rows %v, got %v", tc.rows, rows)
+			}
+		})
+	}
+}
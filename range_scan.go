@@ -0,0 +1,349 @@
+package scylla
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+// ScanRange is a range of tokens from Start exclusive to End inclusive.
+type ScanRange struct {
+	Start Token
+	End   Token
+}
+
+// ScanCheckpoint is the progress of Session.Scan, pass it to ScanOptions.Resume to continue an interrupted scan.
+type ScanCheckpoint struct {
+	// Done holds sorted and disjoint ranges that were scanned completely.
+	Done []ScanRange
+}
+
+type ScanOptions struct {
+	// Keyspace of the table, if empty the session keyspace is used.
+	Keyspace string
+	// Columns to select, if empty all columns are selected.
+	Columns []string
+	// Consistency of the range queries, if zero the session default consistency is used.
+	Consistency Consistency
+	// PageSize of the range queries, if less or equal to 0 the server default is used.
+	PageSize int32
+	// Maximal number of range queries run concurrently.
+	// If less or equal to 0, it's the number of shards of all nodes, so that there is one query per shard.
+	Parallelism int
+	// OnRow is called with every row of the table, it's called concurrently from multiple goroutines.
+	// If it returns an error, the scan is stopped and the error is returned by Session.Scan.
+	OnRow func(frame.Row) error
+	// If not nil, OnCheckpoint is called with the progress after each range is scanned, calls are not concurrent.
+	OnCheckpoint func(ScanCheckpoint)
+	// If not nil, ranges done in Resume are skipped. Rows of ranges that were not completed
+	// before the scan was interrupted are passed to OnRow again.
+	Resume *ScanCheckpoint
+}
+
+// Scan reads all rows of table in parallel, splitting the token ring into ranges owned by a single shard of a replica.
+// Each range is queried with `token(pk) > ? AND token(pk) <= ?` on the connection to the shard that owns it,
+// other replicas are used if the query fails. Rows are passed to opts.OnRow in no particular order.
+func (s *Session) Scan(ctx context.Context, table string, opts ScanOptions) error {
+	if opts.OnRow == nil {
+		return fmt.Errorf("scan options: OnRow is not set")
+	}
+	ks := opts.Keyspace
+	if ks == "" {
+		ks = s.cfg.Keyspace
+	}
+
+	stmt, err := s.prepareScan(ctx, ks, table, &opts)
+	if err != nil {
+		return err
+	}
+
+	p := newScanProgress(opts.Resume)
+	tasks, err := s.scanTasks(ks, p.done)
+	if err != nil {
+		return err
+	}
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = s.shardCount()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		scanErr error
+	)
+	taskCh := make(chan scanTask)
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range taskCh {
+				if err := s.scanRange(ctx, stmt, t, opts.OnRow); err != nil {
+					errOnce.Do(func() {
+						scanErr = err
+						cancel()
+					})
+					return
+				}
+				p.add(t.ScanRange, opts.OnCheckpoint)
+			}
+		}()
+	}
+
+loop:
+	for _, t := range tasks {
+		select {
+		case taskCh <- t:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(taskCh)
+	wg.Wait()
+
+	if scanErr != nil {
+		return scanErr
+	}
+	return ctx.Err()
+}
+
+// prepareScan prepares the range query of ks.table and returns its statement with options applied.
+func (s *Session) prepareScan(ctx context.Context, ks, table string, opts *ScanOptions) (transport.Statement, error) {
+	pk, err := s.partitionKey(ctx, ks, table)
+	if err != nil {
+		return transport.Statement{}, fmt.Errorf("partition key of %s.%s: %w", ks, table, err)
+	}
+	for i := range pk {
+		pk[i] = quoteIdent(pk[i])
+	}
+	cols := "*"
+	if len(opts.Columns) != 0 {
+		quoted := make([]string, len(opts.Columns))
+		for i := range opts.Columns {
+			quoted[i] = quoteIdent(opts.Columns[i])
+		}
+		cols = strings.Join(quoted, ", ")
+	}
+	token := fmt.Sprintf("token(%s)", strings.Join(pk, ", "))
+
+	q, err := s.Prepare(ctx, fmt.Sprintf("SELECT %s FROM %s.%s WHERE %s > ? AND %s <= ?",
+		cols, quoteIdent(ks), quoteIdent(table), token, token))
+	if err != nil {
+		return transport.Statement{}, err
+	}
+
+	stmt := q.stmt
+	stmt.Consistency = opts.Consistency
+	if stmt.Consistency == 0 {
+		stmt.Consistency = s.cfg.DefaultConsistency
+	}
+	if opts.PageSize > 0 {
+		stmt.PageSize = opts.PageSize
+	}
+	return stmt, nil
+}
+
+// shardCount returns the number of shards of all nodes that are up,
+// nodes without sharding info, e.g. Cassandra nodes, are counted as a single shard.
+func (s *Session) shardCount() int {
+	res := 0
+	for _, n := range s.cluster.Topology().Nodes {
+		if !n.IsUp() {
+			continue
+		}
+		if si, ok := n.ShardInfo(); ok {
+			res += int(si.NrShards)
+		} else {
+			res++
+		}
+	}
+	if res == 0 {
+		return 1
+	}
+	return res
+}
+
+// scanTask is a range owned by a single shard of the first replica, the other replicas are used as fallback.
+type scanTask struct {
+	ScanRange
+	replicas []*transport.Node
+}
+
+// scanTasks splits token ranges of keyspace ks, which aren't done yet, into ranges owned by single shards.
+// Primary replicas of consecutive token ranges are rotated to spread the load over all replicas.
+func (s *Session) scanTasks(ks string, done []ScanRange) ([]scanTask, error) {
+	ranges, err := s.cluster.TokenRanges(ks)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []scanTask
+	for i, tr := range ranges {
+		todo := subtractScanRanges(ScanRange{Start: tr.Start, End: tr.End}, done)
+		if len(todo) == 0 {
+			continue
+		}
+		replicas := rotateReplicas(tr.Replicas, i)
+		if len(replicas) == 0 {
+			return nil, fmt.Errorf("no replicas of token range (%d, %d]", tr.Start, tr.End)
+		}
+		// Ranges of nodes without connections are not split, so any connection is used.
+		si, _ := replicas[0].ShardInfo()
+		for _, r := range todo {
+			for _, sr := range splitByShard(r, si) {
+				res = append(res, scanTask{ScanRange: sr, replicas: replicas})
+			}
+		}
+	}
+	return res, nil
+}
+
+// rotateReplicas returns replicas starting with the first one that is up from position i modulo their count.
+func rotateReplicas(replicas []*transport.Node, i int) []*transport.Node {
+	if len(replicas) == 0 {
+		return nil
+	}
+	start := i % len(replicas)
+	for j := range replicas {
+		if k := (i + j) % len(replicas); replicas[k].IsUp() {
+			start = k
+			break
+		}
+	}
+	return append(slices.Clone(replicas[start:]), replicas[:start]...)
+}
+
+// splitByShard splits r into ranges owned by single shards of a node with sharding parameters si.
+func splitByShard(r ScanRange, si transport.ShardInfo) []ScanRange {
+	var res []ScanRange
+	for {
+		// The first token of r is Start+1, which can't overflow as Start < End.
+		next, ok := transport.NextShardToken(si, r.Start+1)
+		if !ok || next > r.End {
+			return append(res, r)
+		}
+		res = append(res, ScanRange{Start: r.Start, End: next - 1})
+		r.Start = next - 1
+	}
+}
+
+// scanRange passes rows of range t to onRow, the query is moved to the next replica if it fails.
+// Paging state doesn't depend on the coordinator, so paging continues where the failed replica stopped.
+func (s *Session) scanRange(ctx context.Context, stmt transport.Statement, t scanTask, onRow func(frame.Row) error) error {
+	stmt = stmt.Clone()
+	if err := bindValue(nil, &stmt.Values[0], int64(t.Start)); err != nil {
+		return err
+	}
+	if err := bindValue(nil, &stmt.Values[1], int64(t.End)); err != nil {
+		return err
+	}
+
+	var (
+		pagingState frame.Bytes
+		lastErr     error
+	)
+	for i := 0; i < len(t.replicas); {
+		n := t.replicas[i]
+		conn, err := n.TokenConn(t.End)
+		if err != nil {
+			lastErr = err
+			i++
+			continue
+		}
+
+		res, err := conn.Execute(ctx, stmt, pagingState)
+		if err != nil {
+			ok, rerr := s.reprepare(ctx, n, err, &stmt)
+			if rerr != nil {
+				return rerr
+			}
+			if ok {
+				res, err = conn.Execute(ctx, stmt, pagingState)
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			i++
+			continue
+		}
+		s.handleWarnings(stmt.Content, &res)
+
+		for _, r := range res.Rows {
+			if err := onRow(r); err != nil {
+				return err
+			}
+		}
+		if !res.HasMorePages {
+			return nil
+		}
+		pagingState = res.PagingState
+	}
+	return fmt.Errorf("scan token range (%d, %d]: %w", t.Start, t.End, lastErr)
+}
+
+// scanProgress tracks ranges that were scanned completely.
+type scanProgress struct {
+	mu   sync.Mutex
+	done []ScanRange
+}
+
+func newScanProgress(resume *ScanCheckpoint) *scanProgress {
+	p := &scanProgress{}
+	if resume != nil {
+		for _, r := range resume.Done {
+			if r.Start < r.End {
+				p.done = addScanRange(p.done, r)
+			}
+		}
+	}
+	return p
+}
+
+// add marks r as done and passes the progress to onCheckpoint if it's not nil.
+func (p *scanProgress) add(r ScanRange, onCheckpoint func(ScanCheckpoint)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = addScanRange(p.done, r)
+	if onCheckpoint != nil {
+		onCheckpoint(ScanCheckpoint{Done: slices.Clone(p.done)})
+	}
+}
+
+// addScanRange adds r to sorted and disjoint ranges, merging it with the ones it overlaps or touches.
+func addScanRange(done []ScanRange, r ScanRange) []ScanRange {
+	i := sort.Search(len(done), func(i int) bool { return done[i].End >= r.Start })
+	j := i
+	for ; j < len(done) && done[j].Start <= r.End; j++ {
+		r.Start = min(r.Start, done[j].Start)
+		r.End = max(r.End, done[j].End)
+	}
+	return slices.Replace(done, i, j, r)
+}
+
+// subtractScanRanges returns parts of r that are not covered by sorted and disjoint ranges done.
+func subtractScanRanges(r ScanRange, done []ScanRange) []ScanRange {
+	var res []ScanRange
+	i := sort.Search(len(done), func(i int) bool { return done[i].End > r.Start })
+	for ; i < len(done) && done[i].Start < r.End; i++ {
+		if done[i].Start > r.Start {
+			res = append(res, ScanRange{Start: r.Start, End: done[i].Start})
+		}
+		r.Start = done[i].End
+		if r.Start >= r.End {
+			return res
+		}
+	}
+	return append(res, r)
+}
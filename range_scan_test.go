@@ -0,0 +1,244 @@
+package scylla
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+func TestAddScanRange(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		done     []ScanRange
+		r        ScanRange
+		expected []ScanRange
+	}{
+		{
+			name:     "empty",
+			r:        ScanRange{Start: 0, End: 10},
+			expected: []ScanRange{{Start: 0, End: 10}},
+		},
+		{
+			name:     "disjoint",
+			done:     []ScanRange{{Start: 0, End: 10}, {Start: 30, End: 40}},
+			r:        ScanRange{Start: 15, End: 20},
+			expected: []ScanRange{{Start: 0, End: 10}, {Start: 15, End: 20}, {Start: 30, End: 40}},
+		},
+		{
+			name:     "overlap",
+			done:     []ScanRange{{Start: 0, End: 10}, {Start: 30, End: 40}},
+			r:        ScanRange{Start: 5, End: 20},
+			expected: []ScanRange{{Start: 0, End: 20}, {Start: 30, End: 40}},
+		},
+		{
+			name:     "touching",
+			done:     []ScanRange{{Start: 0, End: 10}, {Start: 20, End: 30}},
+			r:        ScanRange{Start: 10, End: 20},
+			expected: []ScanRange{{Start: 0, End: 30}},
+		},
+		{
+			name:     "contained",
+			done:     []ScanRange{{Start: 0, End: 10}},
+			r:        ScanRange{Start: 2, End: 8},
+			expected: []ScanRange{{Start: 0, End: 10}},
+		},
+		{
+			name:     "covering many",
+			done:     []ScanRange{{Start: 0, End: 10}, {Start: 20, End: 30}, {Start: 40, End: 50}, {Start: 60, End: 70}},
+			r:        ScanRange{Start: 5, End: 45},
+			expected: []ScanRange{{Start: 0, End: 50}, {Start: 60, End: 70}},
+		},
+		{
+			name:     "edges",
+			done:     []ScanRange{{Start: math.MinInt64, End: -10}, {Start: 10, End: math.MaxInt64}},
+			r:        ScanRange{Start: -10, End: 10},
+			expected: []ScanRange{{Start: math.MinInt64, End: math.MaxInt64}},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := addScanRange(slices.Clone(tc.done), tc.r); !slices.Equal(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSubtractScanRanges(t *testing.T) {
+	t.Parallel()
+
+	full := ScanRange{Start: math.MinInt64, End: math.MaxInt64}
+	testCases := []struct {
+		name     string
+		r        ScanRange
+		done     []ScanRange
+		expected []ScanRange
+	}{
+		{
+			name:     "nothing done",
+			r:        full,
+			expected: []ScanRange{full},
+		},
+		{
+			name:     "all done",
+			r:        full,
+			done:     []ScanRange{full},
+			expected: nil,
+		},
+		{
+			name:     "resume from min",
+			r:        full,
+			done:     []ScanRange{{Start: math.MinInt64, End: 0}},
+			expected: []ScanRange{{Start: 0, End: math.MaxInt64}},
+		},
+		{
+			name:     "done up to max",
+			r:        full,
+			done:     []ScanRange{{Start: 100, End: math.MaxInt64}},
+			expected: []ScanRange{{Start: math.MinInt64, End: 100}},
+		},
+		{
+			name:     "hole in the middle",
+			r:        full,
+			done:     []ScanRange{{Start: math.MinInt64, End: -5}, {Start: 5, End: math.MaxInt64}},
+			expected: []ScanRange{{Start: -5, End: 5}},
+		},
+		{
+			name:     "done ranges outside",
+			r:        ScanRange{Start: 0, End: 10},
+			done:     []ScanRange{{Start: -10, End: 0}, {Start: 10, End: 20}},
+			expected: []ScanRange{{Start: 0, End: 10}},
+		},
+		{
+			name:     "partial overlaps",
+			r:        ScanRange{Start: 0, End: 100},
+			done:     []ScanRange{{Start: -10, End: 10}, {Start: 40, End: 60}, {Start: 90, End: 110}},
+			expected: []ScanRange{{Start: 10, End: 40}, {Start: 60, End: 90}},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := subtractScanRanges(tc.r, tc.done); !slices.Equal(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSplitByShard(t *testing.T) {
+	t.Parallel()
+
+	const stripe = 1 << 51 // Width of a shard stripe with 2 shards and 12 ignored bits.
+	testCases := []struct {
+		name     string
+		r        ScanRange
+		si       transport.ShardInfo
+		expected []ScanRange
+	}{
+		{
+			name:     "single shard",
+			r:        ScanRange{Start: math.MinInt64, End: math.MaxInt64},
+			si:       transport.ShardInfo{NrShards: 1, MsbIgnore: 12},
+			expected: []ScanRange{{Start: math.MinInt64, End: math.MaxInt64}},
+		},
+		{
+			name:     "within stripe",
+			r:        ScanRange{Start: math.MinInt64, End: math.MinInt64 + stripe - 1},
+			si:       transport.ShardInfo{NrShards: 2, MsbIgnore: 12},
+			expected: []ScanRange{{Start: math.MinInt64, End: math.MinInt64 + stripe - 1}},
+		},
+		{
+			name: "msb ignore",
+			r:    ScanRange{Start: math.MinInt64, End: math.MinInt64 + 2*stripe},
+			si:   transport.ShardInfo{NrShards: 2, MsbIgnore: 12},
+			expected: []ScanRange{
+				{Start: math.MinInt64, End: math.MinInt64 + stripe - 1},
+				{Start: math.MinInt64 + stripe - 1, End: math.MinInt64 + 2*stripe - 1},
+				{Start: math.MinInt64 + 2*stripe - 1, End: math.MinInt64 + 2*stripe},
+			},
+		},
+		{
+			name: "no msb ignore",
+			r:    ScanRange{Start: -10, End: 10},
+			si:   transport.ShardInfo{NrShards: 2},
+			expected: []ScanRange{
+				{Start: -10, End: -1},
+				{Start: -1, End: 10},
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := splitByShard(tc.r, tc.si)
+			if !slices.Equal(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+			for _, r := range got {
+				if first, last := transport.ShardOf(tc.si, r.Start+1), transport.ShardOf(tc.si, r.End); first != last {
+					t.Fatalf("range %v spans shards %d and %d", r, first, last)
+				}
+			}
+		})
+	}
+}
+
+func TestRotateReplicas(t *testing.T) {
+	t.Parallel()
+
+	// Nodes created outside of a cluster are down, so replicas are rotated by position.
+	a, b, c := new(transport.Node), new(transport.Node), new(transport.Node)
+	replicas := []*transport.Node{a, b, c}
+	testCases := []struct {
+		name     string
+		replicas []*transport.Node
+		i        int
+		expected []*transport.Node
+	}{
+		{
+			name:     "empty",
+			i:        1,
+			expected: nil,
+		},
+		{
+			name:     "first",
+			replicas: replicas,
+			i:        0,
+			expected: []*transport.Node{a, b, c},
+		},
+		{
+			name:     "middle",
+			replicas: replicas,
+			i:        2,
+			expected: []*transport.Node{c, a, b},
+		},
+		{
+			name:     "modulo",
+			replicas: replicas,
+			i:        4,
+			expected: []*transport.Node{b, c, a},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if got := rotateReplicas(tc.replicas, tc.i); !slices.Equal(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package scylla
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kulezi/scylla-go-driver/transport"
+)
+
+type Token = transport.Token
+
+type NodeInfo = transport.NodeInfo
+
+// TokenRange is a range of tokens from Start exclusive to End inclusive, see Session.TokenRanges.
+type TokenRange struct {
+	Start    Token
+	End      Token
+	Replicas []NodeInfo
+}
+
+// Token returns the token of the partition with key pkValues in table ks.table,
+// values are given in the order of the partition key columns and are encoded like fields in Query.BindStruct.
+// If ks is empty, the session keyspace is used.
+func (s *Session) Token(ctx context.Context, ks, table string, pkValues ...any) (Token, error) {
+	if ks == "" {
+		ks = s.cfg.Keyspace
+	}
+	cols, err := s.partitionKey(ctx, ks, table)
+	if err != nil {
+		return 0, fmt.Errorf("partition key of %s.%s: %w", ks, table, err)
+	}
+	if len(pkValues) != len(cols) {
+		return 0, fmt.Errorf("partition key of %s.%s has %d columns, got %d values", ks, table, len(cols), len(pkValues))
+	}
+
+	where := make([]string, len(cols))
+	for i := range cols {
+		where[i] = quoteIdent(cols[i]) + " = ?"
+	}
+	q, err := s.Prepare(ctx, fmt.Sprintf("SELECT %s FROM %s.%s WHERE %s",
+		quoteIdent(cols[0]), quoteIdent(ks), quoteIdent(table), strings.Join(where, " AND ")))
+	if err != nil {
+		return 0, err
+	}
+	for i, v := range pkValues {
+		if err := bindValue(s.cfg.CodecRegistry, &q.stmt.Values[i], v); err != nil {
+			return 0, fmt.Errorf("bind %s: %w", cols[i], err)
+		}
+	}
+
+	t, ok := q.token()
+	if !ok {
+		return 0, fmt.Errorf("no partition key in bind markers of %s.%s", ks, table)
+	}
+	return t, nil
+}
+
+const partitionKeyQuery = "SELECT column_name, kind, position FROM system_schema.columns WHERE keyspace_name = ? AND table_name = ?"
+
+// partitionKey returns names of partition key columns of ks.table ordered by their position.
+func (s *Session) partitionKey(ctx context.Context, ks, table string) ([]string, error) {
+	q := s.Query(partitionKeyQuery)
+	q.BindText(0, ks).BindText(1, table)
+	res, err := q.Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols []string
+	for _, r := range res.Rows {
+		kind, err := r[1].AsText()
+		if err != nil {
+			return nil, err
+		}
+		if kind != "partition_key" {
+			continue
+		}
+		pos, err := r[2].AsInt32()
+		if err != nil {
+			return nil, err
+		}
+		if pos < 0 || int(pos) >= len(res.Rows) {
+			return nil, fmt.Errorf("invalid partition key position %d", pos)
+		}
+		for int(pos) >= len(cols) {
+			cols = append(cols, "")
+		}
+		if cols[pos], err = r[0].AsText(); err != nil {
+			return nil, err
+		}
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("table doesn't exist")
+	}
+	for i := range cols {
+		if cols[i] == "" {
+			return nil, fmt.Errorf("missing partition key column at position %d", i)
+		}
+	}
+	return cols, nil
+}
+
+// quoteIdent quotes CQL identifier name, so that it's case-sensitive.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Replicas returns nodes owning token t in keyspace ks in ring order across all datacenters,
+// primary replica goes first. It's not the order of query plans, a DC aware TokenAwarePolicy
+// tries replicas from the local datacenter first.
+// If ks is empty, the session keyspace is used.
+func (s *Session) Replicas(ks string, t Token) ([]NodeInfo, error) {
+	nodes, err := s.cluster.Replicas(ks, t)
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfos(nodes), nil
+}
+
+// TokenRanges returns ranges between consecutive tokens of the ring together with their replicas in keyspace ks,
+// see Session.Replicas. Ranges are sorted and cover the whole ring, none of them wraps around it.
+// If ks is empty, the session keyspace is used.
+func (s *Session) TokenRanges(ks string) ([]TokenRange, error) {
+	ranges, err := s.cluster.TokenRanges(ks)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]TokenRange, len(ranges))
+	for i, r := range ranges {
+		res[i] = TokenRange{
+			Start:    r.Start,
+			End:      r.End,
+			Replicas: nodeInfos(r.Replicas),
+		}
+	}
+	return res, nil
+}
+
+func nodeInfos(nodes []*transport.Node) []NodeInfo {
+	res := make([]NodeInfo, len(nodes))
+	for i, n := range nodes {
+		res[i] = n.Info()
+	}
+	return res
+}
+
+// Nodes returns information about all nodes of the cluster known to the driver.
+func (s *Session) Nodes() []NodeInfo {
+	return nodeInfos(s.cluster.Topology().Nodes)
+}
+
+// LocalDC returns the datacenter preferred by the host selection policy, it's empty if the policy isn't DC aware.
+func (s *Session) LocalDC() string {
+	return s.cluster.LocalDC()
+}
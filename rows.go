@@ -0,0 +1,81 @@
+package scylla
+
+import (
+	"context"
+	"iter"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+)
+
+// Rows executes the query and returns an iterator over its rows, pages are fetched as the rows are consumed.
+// Iteration stops after the first error, which is yielded with a nil row.
+// Breaking out of the loop closes the underlying Iter, which stops paging and releases the connection stream.
+func (q *Query) Rows(ctx context.Context) iter.Seq2[frame.Row, error] {
+	return func(yield func(frame.Row, error) bool) {
+		it := q.Iter(ctx)
+		defer it.Close()
+
+		for {
+			row, err := it.Next()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if row == nil {
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+// All executes q and returns an iterator over its rows decoded to values of type T, see Query.Rows.
+// Structs are decoded with Iter.ScanStruct, other types, including structs supported by Iter.Scan
+// such as time.Time, are decoded from the only column of the result.
+func All[T any](ctx context.Context, q *Query) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		it := q.Iter(ctx)
+		defer it.Close()
+
+		scanStruct := isRowStruct(reflect.TypeOf((*T)(nil)).Elem(), it.codecs)
+		for {
+			var (
+				v  T
+				ok bool
+			)
+			if scanStruct {
+				ok = it.ScanStruct(&v)
+			} else {
+				ok = it.Scan(&v)
+			}
+			if !ok {
+				if err := it.Close(); err != nil {
+					yield(v, err)
+				}
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// scalarStructs are struct types decoded from a single column.
+var scalarStructs = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):      true,
+	reflect.TypeOf(big.Int{}):        true,
+	reflect.TypeOf(frame.Duration{}): true,
+	reflect.TypeOf(frame.Decimal{}):  true,
+	reflect.TypeOf(frame.CqlValue{}): true,
+}
+
+// isRowStruct reports whether t is a struct holding whole rows, rather than a value of a single column.
+func isRowStruct(t reflect.Type, codecs *CodecRegistry) bool {
+	return t.Kind() == reflect.Struct && !scalarStructs[t] && !codecs.hasGoType(t)
+}
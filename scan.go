@@ -0,0 +1,181 @@
+package scylla
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"time"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+)
+
+// Scan decodes the first row of the result into dst, see Iter.Scan for supported destinations.
+func (r Result) Scan(dst ...any) error {
+	if len(r.Rows) == 0 {
+		return ErrNoMoreRows
+	}
+	return scanRow(r.codecs, r.ColSpec, r.Rows[0], dst)
+}
+
+// Scan decodes the next row into dst and reports whether it succeeded,
+// on failure the error is returned by Close.
+//
+// Each element of dst must be a pointer to a Go type matching the CQL type of the column:
+// string, []byte, bool, int8, int16, int32, int64, int, float32, float64, [16]byte for UUIDs,
+// net.IP, frame.Duration, time.Time for timestamps and dates, time.Duration for time of day, big.Int, frame.Decimal, []float32 for float vectors,
+// []string and map[string]string for text collections, or frame.CqlValue for raw values.
+// Null values are decoded to zero values, or to nil if dst is a pointer to a pointer, e.g. **int64.
+// Codecs of SessionConfig.CodecRegistry take precedence over the built-in conversions.
+func (it *Iter) Scan(dst ...any) bool {
+	row, err := it.Next()
+	if err != nil || row == nil {
+		return false
+	}
+
+	if err := scanRow(it.codecs, it.Columns(), row, dst); err != nil {
+		it.err = err
+		return false
+	}
+	return true
+}
+
+func scanRow(r *CodecRegistry, cols []frame.ColumnSpec, row frame.Row, dst []any) error {
+	if cols == nil {
+		if len(row) != len(dst) {
+			return fmt.Errorf("column count mismatch, expected %d, got %d", len(row), len(dst))
+		}
+	} else if len(cols) != len(dst) || len(cols) != len(row) {
+		return fmt.Errorf("column count mismatch, expected %d, got %d", len(cols), len(dst))
+	}
+
+	for i := range row {
+		if err := scanValue(r, row[i], dst[i]); err != nil {
+			if cols != nil {
+				return fmt.Errorf("column %s: %w", cols[i].Name, err)
+			}
+			return fmt.Errorf("column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func scanValue(r *CodecRegistry, v frame.CqlValue, dst any) error {
+	if d, ok := dst.(*frame.CqlValue); ok {
+		*d = v
+		return nil
+	}
+	if v.Value == nil {
+		return scanNull(dst)
+	}
+	if ok, err := r.decode(v, dst); ok {
+		return err
+	}
+
+	var err error
+	switch d := dst.(type) {
+	case *string:
+		if v.Type.ID == frame.ASCIIID {
+			*d, err = v.AsASCII()
+		} else {
+			*d, err = v.AsText()
+		}
+	case *[]byte:
+		*d, err = v.AsBlob()
+	case *bool:
+		*d, err = v.AsBoolean()
+	case *int8:
+		*d, err = v.AsInt8()
+	case *int16:
+		*d, err = v.AsInt16()
+	case *int32:
+		*d, err = v.AsInt32()
+	case *int64:
+		if v.Type.ID == frame.CounterID {
+			*d, err = v.AsCounter()
+		} else {
+			*d, err = v.AsInt64()
+		}
+	case *int:
+		*d, err = asInt(v)
+	case *float32:
+		*d, err = v.AsFloat32()
+	case *float64:
+		*d, err = v.AsFloat64()
+	case *[16]byte:
+		if v.Type.ID == frame.TimeUUIDID {
+			*d, err = v.AsTimeUUID()
+		} else {
+			*d, err = v.AsUUID()
+		}
+	case *net.IP:
+		*d, err = v.AsIP()
+	case *frame.Duration:
+		*d, err = v.AsDuration()
+	case *time.Time:
+		*d, err = v.AsTime()
+	case *time.Duration:
+		*d, err = v.AsTimeOfDay()
+	case *big.Int:
+		var x *big.Int
+		if x, err = v.AsVarint(); err == nil {
+			d.Set(x)
+		}
+	case *frame.Decimal:
+		*d, err = v.AsDecimal()
+	case *[]float32:
+		*d, err = v.AsFloat32Vector()
+	case *[]string:
+		*d, err = v.AsStringSlice()
+	case *map[string]string:
+		*d, err = v.AsStringMap()
+	default:
+		return scanIndirect(r, v, dst)
+	}
+	return err
+}
+
+// asInt decodes any of the CQL integer types that fit in int.
+func asInt(v frame.CqlValue) (int, error) {
+	switch v.Type.ID {
+	case frame.TinyIntID:
+		n, err := v.AsInt8()
+		return int(n), err
+	case frame.SmallIntID:
+		n, err := v.AsInt16()
+		return int(n), err
+	case frame.IntID:
+		n, err := v.AsInt32()
+		return int(n), err
+	case frame.CounterID:
+		n, err := v.AsCounter()
+		return int(n), err
+	default:
+		n, err := v.AsInt64()
+		return int(n), err
+	}
+}
+
+// scanIndirect handles pointers to pointers, allocating the value only if it's not null.
+func scanIndirect(r *CodecRegistry, v frame.CqlValue, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Ptr {
+		return fmt.Errorf("can't scan %v into %T", v.Type.ID, dst)
+	}
+
+	p := reflect.New(rv.Elem().Type().Elem())
+	if err := scanValue(r, v, p.Interface()); err != nil {
+		return err
+	}
+	rv.Elem().Set(p)
+	return nil
+}
+
+func scanNull(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("can't scan into %T, not a pointer", dst)
+	}
+	rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	return nil
+}
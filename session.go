@@ -1,12 +1,16 @@
 package scylla
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/kulezi/scylla-go-driver/frame"
+	"github.com/kulezi/scylla-go-driver/frame/response"
 	"github.com/kulezi/scylla-go-driver/transport"
 )
 
@@ -75,10 +79,39 @@ type SessionConfig struct {
 	// Controls the timeout for the automatic wait for schema agreement after sending a schema-altering statement.
 	// If less or equal to 0, the automatic schema agreement is disabled.
 	AutoAwaitSchemaAgreementTimeout time.Duration
+	// Decides when idempotent statements are additionally executed on next nodes.
+	// If nil, speculative execution is disabled.
+	SpeculativeExecutionPolicy transport.SpeculativeExecutionPolicy
+	// Maximal number of prepared statements cached by the session.
+	// If less or equal to 0, statements are prepared on every call to Prepare.
+	// Cached statements are evicted on schema changes, the session registers for them regardless of Events.
+	PreparedCacheSize int
+	// Generates client-side default timestamps of statements and batches.
+	// If nil, timestamps are assigned by coordinators unless set explicitly.
+	TimestampGenerator TimestampGenerator
+	// If not nil, it's called with warnings returned by the server together with the statement that caused them,
+	// e.g. when a read scanned too many tombstones or a write created a large partition.
+	WarningHandler WarningHandler
+	// Codecs of custom Go and CQL types used when binding values and scanning results.
+	// If nil, only the built-in conversions are used.
+	CodecRegistry *CodecRegistry
+	// Size of the events buffer of each subscription, see Session.Subscribe.
+	// If less or equal to 0, the default size of 256 events is used.
+	EventBufferSize int
 
 	transport.ConnConfig
 }
 
+func (s *Session) result(res transport.QueryResult) Result {
+	return Result{
+		QueryResult: res,
+		codecs:      s.cfg.CodecRegistry,
+	}
+}
+
+// WarningHandler receives warnings returned by the server for stmt.
+type WarningHandler func(stmt string, warnings []string)
+
 type DefaultLogger = transport.DefaultLogger
 type DebugLogger = transport.DebugLogger
 
@@ -89,6 +122,8 @@ func DefaultSessionConfig(keyspace string, hosts ...string) SessionConfig {
 		RetryPolicy:                     transport.NewDefaultRetryPolicy(),
 		SchemaAgreementInterval:         200 * time.Millisecond,
 		AutoAwaitSchemaAgreementTimeout: 60 * time.Second,
+		PreparedCacheSize:               1000,
+		TimestampGenerator:              NewMonotonicTimestampGenerator(),
 		ConnConfig:                      transport.DefaultConnConfig(keyspace),
 	}
 }
@@ -123,8 +158,12 @@ func (cfg *SessionConfig) Validate() error {
 }
 
 type Session struct {
-	cfg     SessionConfig
-	cluster *transport.Cluster
+	cfg      SessionConfig
+	cluster  *transport.Cluster
+	prepared *preparedCache
+
+	subs   map[*Subscription]struct{}
+	subsMu sync.Mutex
 }
 
 func NewSession(ctx context.Context, cfg SessionConfig) (*Session, error) {
@@ -134,7 +173,12 @@ func NewSession(ctx context.Context, cfg SessionConfig) (*Session, error) {
 		return nil, err
 	}
 
-	cluster, err := transport.NewCluster(ctx, cfg.ConnConfig, cfg.HostSelectionPolicy, cfg.Events, cfg.Hosts...)
+	events := cfg.Events
+	if cfg.PreparedCacheSize > 0 && !slices.Contains(events, SchemaChange) {
+		// Cached statements are evicted on schema changes.
+		events = append(slices.Clone(events), SchemaChange)
+	}
+	cluster, err := transport.NewCluster(ctx, cfg.ConnConfig, cfg.HostSelectionPolicy, events, cfg.Hosts...)
 	if err != nil {
 		return nil, err
 	}
@@ -143,13 +187,23 @@ func NewSession(ctx context.Context, cfg SessionConfig) (*Session, error) {
 		cfg:     cfg,
 		cluster: cluster,
 	}
+	cluster.SetOnEvent(s.publishEvent)
+
+	if cfg.PreparedCacheSize > 0 {
+		s.prepared = newPreparedCache(cfg.PreparedCacheSize)
+		cluster.SetOnNodeUp(func(ctx context.Context, n *transport.Node) {
+			s.prepared.prepareOnNode(ctx, n, cfg.Logger)
+		})
+	}
 
 	return s, nil
 }
 
 func (s *Session) Query(content string) Query {
 	return Query{session: s,
-		stmt: transport.Statement{Content: content, Consistency: s.cfg.DefaultConsistency},
+		stmt:  transport.Statement{Content: content, Consistency: s.cfg.DefaultConsistency},
+		spec:  s.cfg.SpeculativeExecutionPolicy,
+		tsGen: s.cfg.TimestampGenerator,
 		exec: func(ctx context.Context, conn *transport.Conn, stmt transport.Statement, pagingState frame.Bytes) (transport.QueryResult, error) {
 			return conn.Query(ctx, stmt, pagingState)
 		},
@@ -165,6 +219,28 @@ func (s *Session) Prepare(ctx context.Context, content string) (Query, error) {
 }
 
 func (s *Session) prepareStatement(ctx context.Context, stmt transport.Statement) (Query, error) {
+	if s.prepared == nil {
+		p, err := s.prepareOnAllNodes(ctx, stmt)
+		if err != nil {
+			return Query{}, err
+		}
+		return s.preparedQuery(p), nil
+	}
+
+	k := preparedCacheKey{keyspace: s.cfg.Keyspace, content: stmt.Content}
+	if p, ok := s.prepared.get(k); ok {
+		return s.preparedQuery(withPrepared(stmt, p)), nil
+	}
+
+	p, err := s.prepareOnAllNodes(ctx, stmt)
+	if err != nil {
+		return Query{}, err
+	}
+	s.prepared.put(k, p)
+	return s.preparedQuery(withPrepared(stmt, p)), nil
+}
+
+func (s *Session) prepareOnAllNodes(ctx context.Context, stmt transport.Statement) (transport.Statement, error) {
 	// Prepare on all nodes concurrently.
 	nodes := s.cluster.Topology().Nodes
 	resStmt := make([]transport.Statement, len(nodes))
@@ -182,20 +258,246 @@ func (s *Session) prepareStatement(ctx context.Context, stmt transport.Statement
 	// Find first result that succeeded.
 	for i := range nodes {
 		if resErr[i] == nil {
-			return Query{
-				session: s,
-				stmt:    resStmt[i],
-				exec: func(ctx context.Context, conn *transport.Conn, stmt transport.Statement, pagingState frame.Bytes) (transport.QueryResult, error) {
-					return conn.Execute(ctx, stmt, pagingState)
-				},
-				asyncExec: func(ctx context.Context, conn *transport.Conn, stmt transport.Statement, pagingState frame.Bytes, handler transport.ResponseHandler) {
-					conn.AsyncExecute(ctx, stmt, pagingState, handler)
-				},
-			}, nil
+			return resStmt[i], nil
 		}
 	}
 
-	return Query{}, fmt.Errorf("prepare failed on all nodes, details: %v", resErr)
+	return transport.Statement{}, fmt.Errorf("prepare failed on all nodes, details: %v", resErr)
+}
+
+func (s *Session) preparedQuery(stmt transport.Statement) Query {
+	return Query{
+		session: s,
+		stmt:    stmt,
+		spec:    s.cfg.SpeculativeExecutionPolicy,
+		tsGen:   s.cfg.TimestampGenerator,
+		exec: func(ctx context.Context, conn *transport.Conn, stmt transport.Statement, pagingState frame.Bytes) (transport.QueryResult, error) {
+			return conn.Execute(ctx, stmt, pagingState)
+		},
+		asyncExec: func(ctx context.Context, conn *transport.Conn, stmt transport.Statement, pagingState frame.Bytes, handler transport.ResponseHandler) {
+			conn.AsyncExecute(ctx, stmt, pagingState, handler)
+		},
+	}
+}
+
+// execution executes a request on a connection, prepared are the statements the request uses.
+type execution struct {
+	exec     func(context.Context, *transport.Conn) (transport.QueryResult, error)
+	prepared []*transport.Statement
+	// clone returns execution of a deep copy of the request with prepared statements in the same order.
+	clone func() execution
+}
+
+// execute runs e on consecutive nodes of the host selection plan for info,
+// retrying on failures according to the session's RetryPolicy.
+// If a node doesn't know one of the prepared statements, it's prepared again and the request is replayed.
+// Idempotent requests are additionally executed on next nodes of the plan according to sp,
+// the first successful response is returned and the remaining executions are cancelled.
+// Speculative executions run on clones of e, as losing ones may still be writing their statements
+// to connections after execute returns, prepared statements are copied back from the winner.
+func (s *Session) execute(ctx context.Context, info transport.QueryInfo, idempotent bool, cl frame.Consistency,
+	sp transport.SpeculativeExecutionPolicy, e execution) (transport.QueryResult, error) {
+	plan := &queryPlan{policy: s.cfg.HostSelectionPolicy, info: info}
+	if sp == nil || !idempotent {
+		return s.executeOnPlan(ctx, plan, idempotent, cl, e)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type execResult struct {
+		res transport.QueryResult
+		err error
+		e   execution
+	}
+	results := make(chan execResult)
+	start := func() {
+		c := e.clone()
+		go func() {
+			begin := time.Now()
+			res, err := s.executeOnPlan(ctx, plan, idempotent, cl, c)
+			if err == nil {
+				sp.Observe(time.Since(begin))
+			}
+			select {
+			case results <- execResult{res: res, err: err, e: c}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	var timer *time.Timer
+	var timerC <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	scheduleNext := func(n int) {
+		if d, ok := sp.NextExecution(n); ok {
+			timer = time.NewTimer(d)
+			timerC = timer.C
+		} else {
+			timerC = nil
+		}
+	}
+
+	start()
+	started, inFlight := 1, 1
+	scheduleNext(started)
+	var firstErr error
+	for {
+		select {
+		case r := <-results:
+			inFlight--
+			if r.err == nil {
+				for i := range e.prepared {
+					if e.prepared[i].ID != nil {
+						copyPrepared(e.prepared[i], r.e.prepared[i])
+					}
+				}
+				return r.res, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if inFlight == 0 {
+				return transport.QueryResult{}, firstErr
+			}
+		case <-timerC:
+			start()
+			started++
+			inFlight++
+			scheduleNext(started)
+		case <-ctx.Done():
+			return transport.QueryResult{}, ctx.Err()
+		}
+	}
+}
+
+// queryPlan hands out consecutive nodes returned by the host selection policy,
+// it's shared between speculative executions so that each of them uses different nodes.
+type queryPlan struct {
+	policy transport.HostSelectionPolicy
+	info   transport.QueryInfo
+
+	mu  sync.Mutex
+	idx int
+}
+
+func (p *queryPlan) next() *transport.Node {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := p.policy.Node(p.info, p.idx)
+	p.idx++
+	return n
+}
+
+func (s *Session) executeOnPlan(ctx context.Context, plan *queryPlan, idempotent bool, cl frame.Consistency,
+	e execution) (transport.QueryResult, error) {
+	// Most queries don't need retries, rd will be allocated on first failure.
+	var rd transport.RetryDecider
+	var lastErr error
+	for n := plan.next(); n != nil; n = plan.next() {
+		reprepared := false
+	sameNodeRetries:
+		for {
+			conn, err := n.Conn(plan.info)
+			if err != nil {
+				lastErr = err
+				break sameNodeRetries
+			}
+
+			res, err := e.exec(ctx, conn)
+			if err != nil {
+				// Replaying the request after preparing the statement again isn't counted as a retry.
+				if !reprepared {
+					ok, rerr := s.reprepare(ctx, n, err, e.prepared...)
+					if rerr != nil {
+						return transport.QueryResult{}, rerr
+					}
+					if ok {
+						reprepared = true
+						continue sameNodeRetries
+					}
+				}
+
+				ri := transport.RetryInfo{
+					Error:       err,
+					Idempotent:  idempotent,
+					Consistency: cl,
+				}
+
+				if rd == nil {
+					rd = s.cfg.RetryPolicy.NewRetryDecider()
+				}
+				switch rd.Decide(ri) {
+				case transport.RetrySameNode:
+					continue sameNodeRetries
+				case transport.RetryNextNode:
+					lastErr = err
+					break sameNodeRetries
+				case transport.DontRetry:
+					return transport.QueryResult{}, err
+				}
+			}
+
+			return res, nil
+		}
+	}
+
+	if lastErr == nil {
+		return transport.QueryResult{}, fmt.Errorf("no connection to execute the query on")
+	}
+	return transport.QueryResult{}, lastErr
+}
+
+// reprepare prepares again the statement which node n reported as unknown in err and reports whether it did.
+// Statements are usually unprepared after schema changes, so metadata of the statement is updated as well,
+// bound values are kept and the session's cache is updated. If the number of bind markers has changed
+// values can't be kept and an error is returned.
+func (s *Session) reprepare(ctx context.Context, n *transport.Node, err error, stmts ...*transport.Statement) (bool, error) {
+	var unprepared response.UnpreparedError
+	if !errors.As(err, &unprepared) {
+		return false, nil
+	}
+
+	for _, stmt := range stmts {
+		if stmt.ID == nil || !bytes.Equal(stmt.ID, unprepared.UnknownID) {
+			continue
+		}
+
+		p, err := n.Prepare(ctx, *stmt)
+		if err != nil {
+			return false, nil
+		}
+		if len(p.Values) != len(stmt.Values) {
+			return false, fmt.Errorf("statement %q prepared again has %d bind markers instead of %d, bind values again",
+				stmt.Content, len(p.Values), len(stmt.Values))
+		}
+		copyPrepared(stmt, &p)
+		if s.prepared != nil {
+			s.prepared.put(preparedCacheKey{keyspace: s.cfg.Keyspace, content: p.Content}, p)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// copyPrepared copies the result of preparing the statement from src to dst keeping values bound to dst,
+// both statements must have the same number of bind markers.
+func copyPrepared(dst, src *transport.Statement) {
+	dst.ID = src.ID
+	dst.Keyspace = src.Keyspace
+	dst.Metadata = src.Metadata
+	dst.BindMarkers = src.BindMarkers
+	dst.PkIndexes = src.PkIndexes
+	dst.PkCnt = src.PkCnt
+	dst.LWT = src.LWT
+	for i := range dst.Values {
+		dst.Values[i].Type = src.Values[i].Type
+	}
 }
 
 func (s *Session) AwaitSchemaAgreement(ctx context.Context, timeout time.Duration) error {
@@ -237,6 +539,12 @@ func (s *Session) handleAutoAwaitSchemaAgreement(ctx context.Context, stmt strin
 	return nil
 }
 
+func (s *Session) handleWarnings(stmt string, result *transport.QueryResult) {
+	if s.cfg.WarningHandler != nil && len(result.Warnings) != 0 {
+		s.cfg.WarningHandler(stmt, result.Warnings)
+	}
+}
+
 func (s *Session) CheckSchemaAgreement(ctx context.Context) (bool, error) {
 	// Get schema version from all nodes concurrently.
 	nodes := s.cluster.Topology().Nodes
@@ -278,6 +586,7 @@ func (s *Session) NewTokenAwareDCAwarePolicy(localDC string) transport.HostSelec
 func (s *Session) Close() {
 	s.cfg.Logger.Println("session: close")
 	s.cluster.Close()
+	s.closeSubscriptions()
 }
 
 func (s *Session) Closed() bool {
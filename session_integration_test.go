@@ -3,15 +3,20 @@
 package scylla
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"math/big"
 	"net"
 	"net/netip"
 	"os/signal"
+	"reflect"
+	"sync"
 	"syscall"
 	"testing"
 	"time"
@@ -417,6 +422,47 @@ func TestPrepareIntegration(t *testing.T) {
 	}
 }
 
+func TestPreparedCacheIntegration(t *testing.T) {
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"DROP KEYSPACE IF EXISTS testks",
+		"CREATE KEYSPACE IF NOT EXISTS testks WITH replication = {'class': 'SimpleStrategy', 'replication_factor' : 1}",
+		"CREATE TABLE IF NOT EXISTS testks.doubles (pk bigint PRIMARY KEY, v bigint)",
+	}
+
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := session.PreparedCacheStats()
+	for i := int64(0); i < 10; i++ {
+		q, err := session.Prepare(ctx, "INSERT INTO testks.doubles (pk, v) VALUES (?, ?)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := q.BindInt64(0, i).BindInt64(1, 2*i).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	after := session.PreparedCacheStats()
+	if hits := after.Hits - before.Hits; hits != 9 {
+		t.Fatalf("expected 9 cache hits, got %d", hits)
+	}
+	if misses := after.Misses - before.Misses; misses != 1 {
+		t.Fatalf("expected 1 cache miss, got %d", misses)
+	}
+}
+
 func TestContextsIntegration(t *testing.T) {
 	defer goleak.VerifyNone(t)
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
@@ -1248,3 +1294,1172 @@ var retryTestCases = []struct {
 		idempotent: true,
 	},
 }
+
+func TestBatchIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.triples (pk bigint PRIMARY KEY, v1 bigint, v2 bigint)",
+		"CREATE TABLE IF NOT EXISTS mykeyspace.counters (pk bigint PRIMARY KEY, c counter)",
+		"TRUNCATE mykeyspace.triples",
+		"TRUNCATE mykeyspace.counters",
+	}
+
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insertQuery, err := session.Prepare(ctx, insertStmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const N = 10
+	b := session.Batch(LoggedBatch)
+	b.Add(session.Query("INSERT INTO mykeyspace.triples (pk, v1, v2) VALUES (100, 200, 300)"))
+	for i := int64(0); i < N; i++ {
+		b.Add(*insertQuery.BindInt64(0, i).BindInt64(1, 2*i).BindInt64(2, 3*i))
+	}
+	if b.Size() != N+1 {
+		t.Fatalf("expected batch of size %d, got %d", N+1, b.Size())
+	}
+	if _, err := b.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	selectQuery := session.Query("SELECT pk, v1, v2 FROM mykeyspace.triples")
+	res, err := selectQuery.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Rows) != N+1 {
+		t.Fatalf("expected %d rows, got %d", N+1, len(res.Rows))
+	}
+
+	counterQuery, err := session.Prepare(ctx, "UPDATE mykeyspace.counters SET c = c + 1 WHERE pk = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb := session.Batch(CounterBatch)
+	for i := 0; i < N; i++ {
+		cb.Add(*counterQuery.BindInt64(0, 1))
+	}
+	if _, err := cb.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	selectQuery = session.Query("SELECT c FROM mykeyspace.counters WHERE pk = 1")
+	res, err = selectQuery.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(res.Rows))
+	}
+}
+
+func TestCASIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.triples (pk bigint PRIMARY KEY, v1 bigint, v2 bigint)",
+		"TRUNCATE mykeyspace.triples",
+	}
+
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.triples (pk, v1, v2) VALUES (?, ?, ?) IF NOT EXISTS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	insertQuery.SetSerialConsistency(LOCALSERIAL)
+
+	applied, prev, err := insertQuery.BindInt64(0, 1).BindInt64(1, 2).BindInt64(2, 3).ExecCAS(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !applied || prev != nil {
+		t.Fatalf("expected first insert to be applied, got applied=%v previous=%v", applied, prev)
+	}
+
+	applied, prev, err = insertQuery.BindInt64(0, 1).BindInt64(1, 4).BindInt64(2, 6).ExecCAS(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied {
+		t.Fatal("expected second insert not to be applied")
+	}
+	if len(prev) != 3 {
+		t.Fatalf("expected previous row with 3 columns, got %d", len(prev))
+	}
+	for i, exp := range []int64{1, 2, 3} {
+		if v, err := prev[i].AsInt64(); err != nil {
+			t.Fatal(err)
+		} else if v != exp {
+			t.Fatalf("expected column %d of previous row to be %d, got %d", i, exp, v)
+		}
+	}
+
+	updateQuery, err := session.Prepare(ctx, "UPDATE mykeyspace.triples SET v1 = ? WHERE pk = ? IF v1 = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := session.Batch(LoggedBatch)
+	b.Add(*updateQuery.BindInt64(0, 10).BindInt64(1, 1).BindInt64(2, 2))
+	if applied, _, err := b.ExecCAS(ctx); err != nil {
+		t.Fatal(err)
+	} else if !applied {
+		t.Fatal("expected batch to be applied")
+	}
+
+	if applied, prev, err := b.ExecCAS(ctx); err != nil {
+		t.Fatal(err)
+	} else if applied || len(prev) != 1 {
+		t.Fatalf("expected batch not to be applied with 1 previous row, got applied=%v previous=%v", applied, prev)
+	}
+}
+
+func TestReprepareIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	initKeyspace(ctx, t)
+	cfg := testingSessionConfig
+	// Replaying unprepared statement mustn't depend on retry policy.
+	cfg.RetryPolicy = transport.NewFallthroughRetryPolicy()
+	session, err := NewSession(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	q := session.Query("CREATE TABLE IF NOT EXISTS mykeyspace.triples (pk bigint PRIMARY KEY, v1 bigint, v2 bigint)")
+	if _, err := q.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	insertQuery, err := session.Prepare(ctx, insertStmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selectQuery, err := session.Prepare(ctx, selectStmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unprepared := func(q Query) error {
+		return response.UnpreparedError{
+			ScyllaError: response.ScyllaError{Code: frame.ErrCodeUnprepared},
+			UnknownID:   q.stmt.ID,
+		}
+	}
+
+	w := execWrapper{fakeErrors: []error{unprepared(insertQuery)}}
+	insertQuery.exec = w.wrapExec(insertQuery.exec, t)
+	if _, err := insertQuery.BindInt64(0, 1).BindInt64(1, 2).BindInt64(2, 3).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.queryRecipients) != 2 || w.queryRecipients[0] != w.queryRecipients[1] {
+		t.Fatalf("expected request to be replayed once on the same node, got recipients %v", w.queryRecipients)
+	}
+
+	w = execWrapper{fakeErrors: []error{unprepared(selectQuery)}}
+	selectQuery.exec = w.wrapExec(selectQuery.exec, t)
+	it := selectQuery.BindInt64(0, 1).Iter(ctx)
+	if _, err := it.Next(); err != nil {
+		t.Fatal(err)
+	}
+	it.Close()
+	if len(w.queryRecipients) != 2 || w.queryRecipients[0] != w.queryRecipients[1] {
+		t.Fatalf("expected request to be replayed once on the same node, got recipients %v", w.queryRecipients)
+	}
+}
+
+func TestTracingIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	q := session.Query("SELECT * FROM system.local")
+	q.SetTracing(true)
+	res, err := q.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TracingID == (frame.UUID{}) {
+		t.Fatal("expected tracing ID in the result")
+	}
+
+	traceCtx, traceCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer traceCancel()
+	trace, err := session.FetchTrace(traceCtx, res.TracingID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Coordinator == nil || trace.Duration <= 0 {
+		t.Fatalf("expected complete trace session, got %+v", trace)
+	}
+	if len(trace.Events) == 0 {
+		t.Fatal("expected trace events")
+	}
+}
+
+func TestTimestampIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.timestamps (pk bigint PRIMARY KEY, v bigint)",
+		"TRUNCATE mykeyspace.timestamps",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.timestamps (pk, v) VALUES (?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	selectQuery, err := session.Prepare(ctx, "SELECT WRITETIME(v) FROM mykeyspace.timestamps WHERE pk = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writetime := func(pk int64) int64 {
+		t.Helper()
+		res, err := selectQuery.BindInt64(0, pk).Exec(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := res.Rows[0][0].AsInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	before := time.Now().UnixMicro()
+	if _, err := insertQuery.BindInt64(0, 1).BindInt64(1, 1).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ts := writetime(1); ts < before || ts > time.Now().UnixMicro() {
+		t.Fatalf("expected generated timestamp after %d, got %d", before, ts)
+	}
+
+	insertQuery.SetTimestamp(42)
+	if _, err := insertQuery.BindInt64(0, 2).BindInt64(1, 2).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ts := writetime(2); ts != 42 {
+		t.Fatalf("expected timestamp 42, got %d", ts)
+	}
+
+	// Older write is ignored.
+	insertQuery.SetTimestamp(41)
+	if _, err := insertQuery.BindInt64(0, 2).BindInt64(1, 3).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ts := writetime(2); ts != 42 {
+		t.Fatalf("expected timestamp 42, got %d", ts)
+	}
+
+	insertQuery.SetTimestamp(0)
+	b := session.Batch(UnloggedBatch)
+	b.Add(*insertQuery.BindInt64(0, 3).BindInt64(1, 3))
+	b.SetTimestamp(43)
+	if _, err := b.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ts := writetime(3); ts != 43 {
+		t.Fatalf("expected timestamp 43, got %d", ts)
+	}
+}
+
+func TestScanIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.scan (pk bigint, ck int, name text, score double, tags list<text>, PRIMARY KEY (pk, ck))",
+		"TRUNCATE mykeyspace.scan",
+		"INSERT INTO mykeyspace.scan (pk, ck, name, score, tags) VALUES (1, 1, 'a', 1.5, ['x', 'y'])",
+		"INSERT INTO mykeyspace.scan (pk, ck, name) VALUES (1, 2, 'b')",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q, err := session.Prepare(ctx, "SELECT ck, name, score, tags FROM mykeyspace.scan WHERE pk = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.BindInt64(0, 1)
+
+	var (
+		ck    int32
+		name  string
+		score *float64
+		tags  []string
+	)
+	res, err := q.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Scan(&ck, &name, &score, &tags); err != nil {
+		t.Fatal(err)
+	}
+	if ck != 1 || name != "a" || score == nil || *score != 1.5 || len(tags) != 2 {
+		t.Fatalf("unexpected first row: %v %v %v %v", ck, name, score, tags)
+	}
+
+	it := q.Iter(ctx)
+	n := 0
+	for it.Scan(&ck, &name, &score, &tags) {
+		n++
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 rows, got %d", n)
+	}
+	if ck != 2 || score != nil || tags != nil {
+		t.Fatalf("expected null columns in the last row, got %v %v", score, tags)
+	}
+
+	it = q.Iter(ctx)
+	if it.Scan(&ck, &name) {
+		t.Fatal("expected scan with wrong number of columns to fail")
+	}
+	if err := it.Close(); err == nil {
+		t.Fatal("expected column count mismatch error")
+	}
+
+	it = q.Iter(ctx)
+	if it.Scan(&name, &name, &score, &tags) {
+		t.Fatal("expected scan into wrong type to fail")
+	}
+	if err := it.Close(); err == nil {
+		t.Fatal("expected type mismatch error")
+	}
+}
+
+func TestStructIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.users (user_id bigint PRIMARY KEY, name text, age int, email text)",
+		"TRUNCATE mykeyspace.users",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type user struct {
+		UserID  int64
+		Name    string
+		Years   int32   `cql:"age"`
+		Email   *string `cql:"email"`
+		Ignored string  `cql:"-"`
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.users (user_id, name, age, email) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := "a@example.com"
+	users := []user{
+		{UserID: 1, Name: "a", Years: 20, Email: &email},
+		{UserID: 2, Name: "b", Years: 30},
+	}
+	for i := range users {
+		if _, err := insertQuery.BindStruct(&users[i]).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	selectQuery, err := session.Prepare(ctx, "SELECT user_id, name, age, email FROM mykeyspace.users WHERE user_id IN (1, 2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	it := selectQuery.Iter(ctx)
+	var got []user
+	var u user
+	for it.ScanStruct(&u) {
+		got = append(got, u)
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(got))
+	}
+	for i := range got {
+		if got[i].UserID != users[i].UserID || got[i].Name != users[i].Name || got[i].Years != users[i].Years ||
+			(got[i].Email == nil) != (users[i].Email == nil) {
+			t.Fatalf("expected %+v, got %+v", users[i], got[i])
+		}
+	}
+
+	// Fields promoted through nil embedded pointers are bound as null and allocated when scanning.
+	type Contact struct {
+		Email *string
+	}
+	type contactUser struct {
+		UserID int64
+		Name   string
+		Years  int32 `cql:"age"`
+		*Contact
+	}
+	if _, err := insertQuery.BindStruct(contactUser{UserID: 3, Name: "c"}).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	selectQuery, err = session.Prepare(ctx, "SELECT user_id, name, age, email FROM mykeyspace.users WHERE user_id = 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	it = selectQuery.Iter(ctx)
+	var cu contactUser
+	if !it.ScanStruct(&cu) {
+		t.Fatalf("expected a row, got %v", it.Close())
+	}
+	if err := it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if cu.Contact == nil || cu.Email == nil || *cu.Email != email {
+		t.Fatalf("expected email %s, got %+v", email, cu.Contact)
+	}
+
+	type missing struct {
+		UserID int64
+	}
+	if _, err := insertQuery.BindStruct(missing{UserID: 4}).Exec(ctx); err == nil {
+		t.Fatal("expected error for bind markers without fields")
+	}
+}
+
+func TestBindNameIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.named (pk bigint, ck bigint, v bigint, PRIMARY KEY (pk, ck))",
+		"TRUNCATE mykeyspace.named",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.named (pk, ck, v) VALUES (?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insertQuery.BindNameInt64("v", 10).BindNameInt64("ck", 1).BindNameInt64("pk", 1).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	unknown, err := session.Prepare(ctx, "INSERT INTO mykeyspace.named (pk, ck, v) VALUES (?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unknown.BindNameInt64("x", 1).Exec(ctx); err == nil {
+		t.Fatal("expected error for unknown bind marker name")
+	}
+
+	ambiguous, err := session.Prepare(ctx, "SELECT v FROM mykeyspace.named WHERE pk = ? AND ck > ? AND ck < ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ambiguous.BindNameInt64("ck", 1).Exec(ctx); err == nil {
+		t.Fatal("expected error for ambiguous bind marker name")
+	}
+
+	namedQuery := session.Query("INSERT INTO mykeyspace.named (pk, ck, v) VALUES (:pk, :ck, :v)")
+	namedQuery.BindNameInt64("pk", 1).BindNameInt64("ck", 4).BindNameInt64("v", 40)
+	if _, err := session.Batch(UnloggedBatch).Add(namedQuery).Exec(ctx); err == nil {
+		t.Fatal("expected error for named values in batch")
+	}
+
+	// Names of prepared statements are resolved by the driver, so they can be used in batches.
+	b := session.Batch(UnloggedBatch)
+	b.Add(*insertQuery.BindNameInt64("pk", 1).BindNameInt64("ck", 2).BindNameInt64("v", 20))
+	b.Add(*insertQuery.BindNameInt64("pk", 1).BindNameInt64("ck", 3).BindNameInt64("v", 30))
+	if _, err := b.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	q := session.Query("SELECT ck, v FROM mykeyspace.named WHERE pk = :pk")
+	res, err := q.BindNameInt64("pk", 1).Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(res.Rows))
+	}
+	for i, row := range res.Rows {
+		var ck, v int64
+		if err := scanRow(nil, res.ColSpec, row, []any{&ck, &v}); err != nil {
+			t.Fatal(err)
+		}
+		if ck != int64(i+1) || v != 10*ck {
+			t.Fatalf("expected (%d, %d), got (%d, %d)", i+1, 10*(i+1), ck, v)
+		}
+	}
+}
+
+func TestTypedBindIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.typed (pk int PRIMARY KEY, a ascii, t text, b blob, bo boolean, " +
+			"si smallint, ti tinyint, f float, d double, u uuid, ip inet, ts timestamp, dt date, tm time, " +
+			"du duration, vi varint, de decimal)",
+		"TRUNCATE mykeyspace.typed",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.typed (pk, a, t, b, bo, si, ti, f, d, u, ip, ts, dt, tm, du, vi, de) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	insertQuery.BindInt32(0, 1).BindText(1, "ascii").BindText(2, "text").BindBlob(3, []byte{1, 2}).BindBool(4, true).
+		BindInt16(5, 2).BindInt8(6, 3).BindFloat32(7, 4.5).BindFloat64(8, 5.5).BindUUID(9, [16]byte{1}).
+		BindIP(10, net.IPv4(127, 0, 0, 1).To4()).BindTimestamp(11, now).BindDate(12, now).BindTime(13, time.Hour).
+		BindDuration(14, frame.Duration{Days: 1}).BindVarint(15, big.NewInt(-129)).
+		BindDecimal(16, big.NewInt(12345), 2)
+	if _, err := insertQuery.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unset columns keep their values, null columns are deleted.
+	insertQuery.BindInt32(0, 1).BindNull(1)
+	for i := 2; i < 17; i++ {
+		insertQuery.BindUnset(i)
+	}
+	if _, err := insertQuery.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	selectQuery := session.Query("SELECT a, t, vi FROM mykeyspace.typed WHERE pk = 1")
+	res, err := selectQuery.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		a, txt string
+		vi     frame.CqlValue
+	)
+	if err := res.Scan(&a, &txt, &vi); err != nil {
+		t.Fatal(err)
+	}
+	if a != "" || txt != "text" {
+		t.Fatalf("expected null and text, got %q and %q", a, txt)
+	}
+	if !bytes.Equal(vi.Value, frame.Bytes{0xff, 0x7f}) {
+		t.Fatalf("expected varint -129, got %v", vi.Value)
+	}
+
+	mismatched, err := session.Prepare(ctx, "INSERT INTO mykeyspace.typed (pk, si) VALUES (?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mismatched.BindInt32(0, 2).BindInt32(1, 1).Exec(ctx); err == nil {
+		t.Fatal("expected error for int bound to smallint")
+	}
+}
+
+type testColor int
+
+const (
+	testRed testColor = iota + 1
+	testGreen
+)
+
+var testColorNames = map[testColor]string{testRed: "red", testGreen: "green"}
+
+func TestCodecRegistryIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	codecs := NewCodecRegistry()
+	RegisterCodec(codecs, frame.Option{ID: frame.VarcharID},
+		func(v testColor, _ *frame.Option) ([]byte, error) {
+			name, ok := testColorNames[v]
+			if !ok {
+				return nil, fmt.Errorf("unknown color %d", v)
+			}
+			return []byte(name), nil
+		},
+		func(v frame.CqlValue) (testColor, error) {
+			for c, name := range testColorNames {
+				if name == string(v.Value) {
+					return c, nil
+				}
+			}
+			return 0, fmt.Errorf("unknown color %s", v.Value)
+		})
+
+	initKeyspace(ctx, t)
+	cfg := testingSessionConfig
+	cfg.CodecRegistry = codecs
+	session, err := NewSession(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.colors (pk int PRIMARY KEY, color text)",
+		"TRUNCATE mykeyspace.colors",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type row struct {
+		PK    int32 `cql:"pk"`
+		Color testColor
+	}
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.colors (pk, color) VALUES (?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insertQuery.BindStruct(row{PK: 1, Color: testGreen}).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insertQuery.BindStruct(row{PK: 2, Color: testColor(7)}).Exec(ctx); err == nil {
+		t.Fatal("expected encoding error")
+	}
+
+	selectQuery := session.Query("SELECT color FROM mykeyspace.colors WHERE pk = 1")
+	res, err := selectQuery.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c testColor
+	if err := res.Scan(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c != testGreen {
+		t.Fatalf("expected %d, got %d", testGreen, c)
+	}
+	var s string
+	if err := res.Scan(&s); err != nil {
+		t.Fatal(err)
+	}
+	if s != "green" {
+		t.Fatalf("expected green, got %s", s)
+	}
+}
+
+func TestVectorIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.embeddings (pk int PRIMARY KEY, v vector<float, 3>)",
+		"TRUNCATE mykeyspace.embeddings",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.embeddings (pk, v) VALUES (?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := insertQuery.stmt.BindMarkers[1].Type.Vector; v == nil || v.Dimension != 3 || v.Element.ID != frame.FloatID {
+		t.Fatalf("expected vector<float, 3> bind marker, got %+v", insertQuery.stmt.BindMarkers[1].Type)
+	}
+	embedding := []float32{0.5, -1, 2}
+	if _, err := insertQuery.BindInt32(0, 1).BindFloat32Vector(1, embedding).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insertQuery.BindInt32(0, 2).BindFloat32Vector(1, []float32{1}).Exec(ctx); err == nil {
+		t.Fatal("expected error for vector of wrong dimension")
+	}
+
+	selectQuery := session.Query("SELECT v FROM mykeyspace.embeddings WHERE pk = 1")
+	res, err := selectQuery.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []float32
+	if err := res.Scan(&got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(embedding) || got[0] != embedding[0] || got[1] != embedding[1] || got[2] != embedding[2] {
+		t.Fatalf("expected %v, got %v", embedding, got)
+	}
+}
+
+func TestRowsIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	initStmts := []string{
+		"CREATE TABLE IF NOT EXISTS mykeyspace.rows (pk bigint, ck bigint, v text, PRIMARY KEY (pk, ck))",
+		"TRUNCATE mykeyspace.rows",
+	}
+	for _, stmt := range initStmts {
+		q := session.Query(stmt)
+		if _, err := q.Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.rows (pk, ck, v) VALUES (1, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 100
+	for i := int64(0); i < n; i++ {
+		if _, err := insertQuery.BindInt64(0, i).BindText(1, fmt.Sprint(i)).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	q := session.Query("SELECT ck, v FROM mykeyspace.rows WHERE pk = 1")
+	q.SetPageSize(10)
+	cnt := 0
+	for row, err := range q.Rows(ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ck, err := row[0].AsInt64(); err != nil || ck != int64(cnt) {
+			t.Fatalf("expected %d, got %d: %v", cnt, ck, err)
+		}
+		cnt++
+	}
+	if cnt != n {
+		t.Fatalf("expected %d rows, got %d", n, cnt)
+	}
+
+	// Breaking out in the middle of a page and right at its end must release the worker, goleak checks that.
+	for _, limit := range []int{5, 10} {
+		cnt = 0
+		for _, err := range q.Rows(ctx) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			cnt++
+			if cnt == limit {
+				break
+			}
+		}
+	}
+
+	type row struct {
+		CK int64
+		V  string
+	}
+	cnt = 0
+	for r, err := range All[row](ctx, &q) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.CK != int64(cnt) || r.V != fmt.Sprint(cnt) {
+			t.Fatalf("expected row %d, got %+v", cnt, r)
+		}
+		cnt++
+	}
+	if cnt != n {
+		t.Fatalf("expected %d rows, got %d", n, cnt)
+	}
+
+	vq := session.Query("SELECT v FROM mykeyspace.rows WHERE pk = 1 LIMIT 3")
+	var values []string
+	for v, err := range All[string](ctx, &vq) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, v)
+	}
+	if len(values) != 3 || values[0] != "0" {
+		t.Fatalf("expected 3 values starting with 0, got %v", values)
+	}
+}
+
+func TestStatementKeyspaceIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	initKeyspace(ctx, t)
+	cfg := testingSessionConfig
+	cfg.Keyspace = ""
+	session, err := NewSession(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	q := session.Query("CREATE TABLE IF NOT EXISTS mykeyspace.qualified (pk bigint PRIMARY KEY, v bigint)")
+	if _, err := q.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.qualified (pk, v) VALUES (?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if insertQuery.stmt.Keyspace != "mykeyspace" {
+		t.Fatalf("expected keyspace mykeyspace, got %q", insertQuery.stmt.Keyspace)
+	}
+	if _, err := insertQuery.info(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insertQuery.BindInt64(0, 1).BindInt64(1, 2).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTokenRingIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	q := session.Query("CREATE TABLE IF NOT EXISTS mykeyspace.composite_pk (a bigint, b text, c bigint, PRIMARY KEY ((a, b), c))")
+	if _, err := q.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.composite_pk (a, b, c) VALUES (?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	insertQuery.BindInt64(0, 1).BindText(1, "x").BindInt64(2, 2)
+	expected, ok := insertQuery.token()
+	if !ok {
+		t.Fatal("insert query is not token aware")
+	}
+
+	token, err := session.Token(ctx, "mykeyspace", "composite_pk", int64(1), "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != expected {
+		t.Fatalf("expected token %d, got %d", expected, token)
+	}
+	if _, err := session.Token(ctx, "mykeyspace", "composite_pk", int64(1)); err == nil {
+		t.Fatal("expected error for missing partition key value")
+	}
+
+	replicas, err := session.Replicas("mykeyspace", token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replicas) == 0 {
+		t.Fatal("expected replicas")
+	}
+
+	ranges, err := session.TokenRanges("mykeyspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for i, r := range ranges {
+		if i > 0 && r.Start != ranges[i-1].End {
+			t.Fatalf("range %d doesn't start where the previous one ends", i)
+		}
+		if r.Start < token && token <= r.End {
+			found = true
+			if !reflect.DeepEqual(r.Replicas, replicas) {
+				t.Fatalf("replicas of range %v don't match replicas of token: %v", r, replicas)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("token %d is not in any range", token)
+	}
+}
+
+func TestTableScanIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	q := session.Query("CREATE TABLE IF NOT EXISTS mykeyspace.scan (pk bigint, ck bigint, v text, PRIMARY KEY (pk, ck))")
+	if _, err := q.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	truncateQuery := session.Query("TRUNCATE mykeyspace.scan")
+	if _, err := truncateQuery.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 1000
+	insertQuery, err := session.Prepare(ctx, "INSERT INTO mykeyspace.scan (pk, ck, v) VALUES (?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := int64(0); i < n; i++ {
+		if _, err := insertQuery.BindInt64(0, i%100).BindInt64(1, i).BindText(2, "v").Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[int64]int)
+	)
+	onRow := func(r frame.Row) error {
+		ck, err := r[0].AsInt64()
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		seen[ck]++
+		mu.Unlock()
+		return nil
+	}
+
+	var last ScanCheckpoint
+	err = session.Scan(ctx, "scan", ScanOptions{
+		Columns:      []string{"ck"},
+		PageSize:     10,
+		Parallelism:  4,
+		OnRow:        onRow,
+		OnCheckpoint: func(c ScanCheckpoint) { last = c },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != n {
+		t.Fatalf("expected %d rows, got %d", n, len(seen))
+	}
+	for ck, cnt := range seen {
+		if cnt != 1 {
+			t.Fatalf("row %d was scanned %d times", ck, cnt)
+		}
+	}
+	if len(last.Done) != 1 || last.Done[0].Start != math.MinInt64 || last.Done[0].End != math.MaxInt64 {
+		t.Fatalf("expected the whole ring to be done, got %v", last.Done)
+	}
+
+	// Interrupt the scan and resume it from the last checkpoint.
+	seen = make(map[int64]int)
+	last = ScanCheckpoint{}
+	errStop := errors.New("stop")
+	err = session.Scan(ctx, "scan", ScanOptions{
+		Columns:     []string{"ck"},
+		Parallelism: 1,
+		OnRow: func(r frame.Row) error {
+			mu.Lock()
+			stop := len(seen) >= n/2
+			mu.Unlock()
+			if stop {
+				return errStop
+			}
+			return onRow(r)
+		},
+		OnCheckpoint: func(c ScanCheckpoint) { last = c },
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected stop error, got %v", err)
+	}
+	err = session.Scan(ctx, "scan", ScanOptions{
+		Columns: []string{"ck"},
+		OnRow:   onRow,
+		Resume:  &last,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != n {
+		t.Fatalf("expected %d rows after resuming, got %d", n, len(seen))
+	}
+}
+
+func TestNodesIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	session := newTestSession(ctx, t)
+	defer session.Close()
+
+	if dc := session.LocalDC(); dc != "" {
+		t.Fatalf("expected no local DC, got %q", dc)
+	}
+
+	nodes := session.Nodes()
+	if len(nodes) == 0 {
+		t.Fatal("expected nodes")
+	}
+	for _, n := range nodes {
+		if n.Addr == "" || n.Datacenter == "" || n.Rack == "" || n.HostID == (frame.UUID{}) {
+			t.Fatalf("incomplete node info: %+v", n)
+		}
+		if !n.Up {
+			t.Fatalf("node %s is down", n.Addr)
+		}
+		if n.Shards == 0 || len(n.OpenConns) != n.Shards {
+			t.Fatalf("node %s: expected connections of %d shards, got %v", n.Addr, n.Shards, n.OpenConns)
+		}
+		if len(n.Tokens) == 0 {
+			t.Fatalf("node %s owns no tokens", n.Addr)
+		}
+		for i := 1; i < len(n.Tokens); i++ {
+			if n.Tokens[i-1] >= n.Tokens[i] {
+				t.Fatalf("node %s: tokens are not sorted", n.Addr)
+			}
+		}
+	}
+
+	cfg := testingSessionConfig
+	cfg.HostSelectionPolicy = transport.NewTokenAwarePolicy(nodes[0].Datacenter)
+	dcSession, err := NewSession(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dcSession.Close()
+
+	if dc := dcSession.LocalDC(); dc != nodes[0].Datacenter {
+		t.Fatalf("expected local DC %s, got %q", nodes[0].Datacenter, dc)
+	}
+}
+
+func TestSubscribeIntegration(t *testing.T) { // nolint:paralleltest // Integration tests are not run in parallel!
+	defer goleak.VerifyNone(t)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGABRT, syscall.SIGTERM)
+	defer cancel()
+
+	initKeyspace(ctx, t)
+	cfg := testingSessionConfig
+	cfg.Events = []EventType{SchemaChange}
+	cfg.EventBufferSize = 1
+	session, err := NewSession(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	if _, err := session.Subscribe(TopologyChange); err == nil {
+		t.Fatal("expected error when subscribing to events that are not registered")
+	}
+	sub, err := session.Subscribe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := session.Query("CREATE TABLE IF NOT EXISTS mykeyspace.subscribe (pk bigint PRIMARY KEY)")
+	if _, err := q.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	q = session.Query("DROP TABLE mykeyspace.subscribe")
+	if _, err := q.Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second event doesn't fit in the buffer of size 1 until the first one is received.
+	deadline := time.Now().Add(10 * time.Second)
+	for sub.Dropped() == 0 && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if sub.Dropped() == 0 {
+		t.Fatal("expected dropped events")
+	}
+
+	select {
+	case e := <-sub.Events():
+		v, ok := e.(*SchemaChangeEvent)
+		if !ok {
+			t.Fatalf("expected schema change event, got %#v", e)
+		}
+		if v.Keyspace != "mykeyspace" || v.Object != "subscribe" {
+			t.Fatalf("unexpected schema change event %+v", v)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("no schema change event received")
+	}
+
+	sub.Close()
+	for range sub.Events() {
+	}
+}
@@ -0,0 +1,225 @@
+package scylla
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+)
+
+// structPlan maps columns or bind markers of a statement to fields of a struct type.
+type structPlan struct {
+	typ    reflect.Type
+	names  []string
+	fields [][]int
+}
+
+type structPlanKey struct {
+	typ  reflect.Type
+	cols string
+}
+
+// structPlans caches plans by struct type and column names, so that reflection is done once per statement shape.
+// Both come from the program, so the cache is bounded by the number of distinct statements it uses.
+var structPlans sync.Map // map[structPlanKey]*structPlan
+
+// columnsKey returns names of cols in a form that uniquely identifies them, names are length-prefixed
+// as quoted identifiers can contain any characters.
+func columnsKey(cols []frame.ColumnSpec) string {
+	var b strings.Builder
+	for i := range cols {
+		b.WriteString(strconv.Itoa(len(cols[i].Name)))
+		b.WriteByte(':')
+		b.WriteString(cols[i].Name)
+	}
+	return b.String()
+}
+
+// getStructPlan returns plan of t for columns cols.
+func getStructPlan(t reflect.Type, cols []frame.ColumnSpec) (*structPlan, error) {
+	k := structPlanKey{typ: t, cols: columnsKey(cols)}
+	if v, ok := structPlans.Load(k); ok {
+		return v.(*structPlan), nil
+	}
+
+	p, err := makeStructPlan(t, cols)
+	if err != nil {
+		return nil, err
+	}
+	structPlans.Store(k, p)
+	return p, nil
+}
+
+func makeStructPlan(t reflect.Type, cols []frame.ColumnSpec) (*structPlan, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%v is not a struct", t)
+	}
+
+	byName := structFields(t)
+	p := &structPlan{
+		typ:    t,
+		names:  make([]string, len(cols)),
+		fields: make([][]int, len(cols)),
+	}
+	for i := range cols {
+		idx, ok := byName[cols[i].Name]
+		if !ok {
+			return nil, fmt.Errorf("%v has no field for %s", t, cols[i].Name)
+		}
+		p.names[i] = cols[i].Name
+		p.fields[i] = idx
+	}
+	return p, nil
+}
+
+// structFields returns indexes of exported fields of t, including fields of embedded structs, by column name.
+// Column name of a field is set by the cql tag or is the field name converted to snake_case,
+// fields tagged with `cql:"-"` are ignored.
+func structFields(t reflect.Type) map[string][]int {
+	res := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous && f.Type.Kind() == reflect.Struct {
+			continue
+		}
+
+		name := f.Tag.Get("cql")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = toSnakeCase(f.Name)
+		}
+		// Fields of outer structs shadow fields of embedded ones.
+		if idx, ok := res[name]; !ok || len(idx) > len(f.Index) {
+			res[name] = f.Index
+		}
+	}
+	return res
+}
+
+// toSnakeCase converts Go identifiers such as UserID or HTTPServer to user_id and http_server.
+func toSnakeCase(s string) string {
+	r := []rune(s)
+	var b strings.Builder
+	for i := range r {
+		if unicode.IsUpper(r[i]) {
+			if i > 0 && (!unicode.IsUpper(r[i-1]) || i+1 < len(r) && unicode.IsLower(r[i+1])) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r[i]))
+		} else {
+			b.WriteRune(r[i])
+		}
+	}
+	return b.String()
+}
+
+// structValue returns the struct v points to or is.
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%T is not a struct", v)
+	}
+	return rv, nil
+}
+
+// BindStruct binds fields of struct v to bind markers of the prepared query with the same names,
+// see structFields for how fields are named. Every bind marker must have a matching field.
+func (q *Query) BindStruct(v any) *Query {
+	if q.stmt.Metadata == nil {
+		q.err = append(q.err, fmt.Errorf("binding structs to unprepared queries is not supported"))
+		return q
+	}
+
+	rv, err := structValue(v)
+	if err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
+	p, err := getStructPlan(rv.Type(), q.stmt.BindMarkers)
+	if err != nil {
+		q.err = append(q.err, err)
+		return q
+	}
+
+	for i, idx := range p.fields {
+		// Fields promoted through nil embedded pointers are bound as null.
+		var x any
+		if f, err := rv.FieldByIndexErr(idx); err == nil {
+			x = f.Interface()
+		}
+		if err := bindValue(q.session.cfg.CodecRegistry, &q.stmt.Values[i], x); err != nil {
+			q.err = append(q.err, fmt.Errorf("bind %s: %w", p.names[i], err))
+		}
+	}
+	return q
+}
+
+// ScanStruct decodes the next row into fields of the struct dst points to and reports whether it succeeded,
+// on failure the error is returned by Close. Every column must have a matching field, see Query.BindStruct.
+func (it *Iter) ScanStruct(dst any) bool {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		it.err = fmt.Errorf("can't scan into %T, not a pointer to a struct", dst)
+		return false
+	}
+	rv = rv.Elem()
+
+	row, err := it.Next()
+	if err != nil || row == nil {
+		return false
+	}
+
+	cols := it.Columns()
+	if it.plan == nil || it.plan.typ != rv.Type() {
+		if it.plan, err = getStructPlan(rv.Type(), cols); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	if len(row) != len(it.plan.fields) {
+		it.err = fmt.Errorf("column count mismatch, expected %d, got %d", len(it.plan.fields), len(row))
+		return false
+	}
+
+	for i, idx := range it.plan.fields {
+		f, err := fieldByIndexAlloc(rv, idx)
+		if err != nil {
+			it.err = fmt.Errorf("column %s: %w", it.plan.names[i], err)
+			return false
+		}
+		if err := scanValue(it.codecs, row[i], f.Addr().Interface()); err != nil {
+			it.err = fmt.Errorf("column %s: %w", it.plan.names[i], err)
+			return false
+		}
+	}
+	return true
+}
+
+// fieldByIndexAlloc returns the nested field of v with index idx, nil embedded pointers on the way are
+// set to newly allocated structs.
+func fieldByIndexAlloc(v reflect.Value, idx []int) (reflect.Value, error) {
+	for i, x := range idx {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("can't set nil pointer to unexported embedded struct %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
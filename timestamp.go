@@ -0,0 +1,36 @@
+package scylla
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// TimestampGenerator generates client-side timestamps of statements in microseconds since the Unix epoch.
+// It's used concurrently by all queries of the session.
+type TimestampGenerator interface {
+	Next() int64
+}
+
+// MonotonicTimestampGenerator returns current time in microseconds,
+// if it was already returned or the clock went backwards the last timestamp incremented by one is returned instead.
+type MonotonicTimestampGenerator struct {
+	last atomic.Int64
+}
+
+func NewMonotonicTimestampGenerator() *MonotonicTimestampGenerator {
+	return &MonotonicTimestampGenerator{}
+}
+
+func (g *MonotonicTimestampGenerator) Next() int64 {
+	for {
+		last := g.last.Load()
+		next := time.Now().UnixMicro()
+		if next <= last {
+			next = last + 1
+		}
+		if g.last.CAS(last, next) {
+			return next
+		}
+	}
+}
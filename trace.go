@@ -0,0 +1,190 @@
+package scylla
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+)
+
+// Trace describes execution of a traced request, see Query.SetTracing.
+type Trace struct {
+	ID          frame.UUID
+	Coordinator net.IP
+	Request     string
+	Parameters  map[string]string
+	StartedAt   time.Time
+	// Duration is the total time of the request measured by the coordinator.
+	Duration time.Duration
+	Events   []TraceEvent
+}
+
+// TraceEvent is a single step of request execution on one of the nodes.
+type TraceEvent struct {
+	ID       frame.UUID
+	Activity string
+	Source   net.IP
+	// SourceElapsed is the time since the request was received by Source.
+	SourceElapsed time.Duration
+	Thread        string
+}
+
+const traceFetchInterval = 100 * time.Millisecond
+
+// FetchTrace polls system_traces until the trace with given id is complete and returns it.
+// The trace is complete when its duration is set and the number of its events is stable between polls.
+// Traces are written asynchronously by the nodes, so ctx should have a deadline.
+func (s *Session) FetchTrace(ctx context.Context, id frame.UUID) (Trace, error) {
+	t := Trace{ID: id}
+
+	q := s.Query(fmt.Sprintf("SELECT coordinator, request, parameters, started_at, duration "+
+		"FROM system_traces.sessions WHERE session_id = %s", uuidString(id)))
+	q.stmt.Consistency = frame.ONE
+
+	ticker := time.NewTicker(traceFetchInterval)
+	defer ticker.Stop()
+	for {
+		res, err := q.Exec(ctx)
+		if err != nil {
+			return Trace{}, fmt.Errorf("fetch trace session: %w", err)
+		}
+		// Duration is written when the request finishes.
+		if len(res.Rows) == 1 && res.Rows[0][4].Value != nil {
+			if err := t.parseSession(res.Rows[0]); err != nil {
+				return Trace{}, fmt.Errorf("parse trace session: %w", err)
+			}
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return Trace{}, fmt.Errorf("trace %s not complete: %w", uuidString(id), ctx.Err())
+		}
+	}
+
+	// Events of other nodes may still be written after the coordinator has finished,
+	// they are read until their number doesn't change between reads.
+	q = s.Query(fmt.Sprintf("SELECT event_id, activity, source, source_elapsed, thread "+
+		"FROM system_traces.events WHERE session_id = %s", uuidString(id)))
+	q.stmt.Consistency = frame.ONE
+	for n := -1; ; {
+		events, err := fetchTraceEvents(ctx, q)
+		if err != nil {
+			return Trace{}, err
+		}
+		t.Events = events
+		if len(events) == n {
+			break
+		}
+		n = len(events)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return Trace{}, fmt.Errorf("trace %s events not complete: %w", uuidString(id), ctx.Err())
+		}
+	}
+
+	return t, nil
+}
+
+func fetchTraceEvents(ctx context.Context, q Query) ([]TraceEvent, error) {
+	var events []TraceEvent
+	it := q.Iter(ctx)
+	defer it.Close()
+	for {
+		row, err := it.Next()
+		if err != nil {
+			return nil, fmt.Errorf("fetch trace events: %w", err)
+		}
+		if row == nil {
+			break
+		}
+
+		e, err := parseTraceEvent(row)
+		if err != nil {
+			return nil, fmt.Errorf("parse trace event: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := it.Close(); err != nil {
+		return nil, fmt.Errorf("fetch trace events: %w", err)
+	}
+	return events, nil
+}
+
+func (t *Trace) parseSession(row frame.Row) error {
+	var err error
+	if t.Coordinator, err = traceIP(row[0]); err != nil {
+		return err
+	}
+	if t.Request, err = traceText(row[1]); err != nil {
+		return err
+	}
+	if row[2].Value != nil {
+		if t.Parameters, err = row[2].AsStringMap(); err != nil {
+			return err
+		}
+	}
+	if row[3].Value != nil {
+		if t.StartedAt, err = row[3].AsTimestamp(); err != nil {
+			return err
+		}
+	}
+	t.Duration, err = traceMicroseconds(row[4])
+	return err
+}
+
+func parseTraceEvent(row frame.Row) (TraceEvent, error) {
+	var (
+		e   TraceEvent
+		err error
+	)
+	if e.ID, err = row[0].AsTimeUUID(); err != nil {
+		return TraceEvent{}, err
+	}
+	if e.Activity, err = traceText(row[1]); err != nil {
+		return TraceEvent{}, err
+	}
+	if e.Source, err = traceIP(row[2]); err != nil {
+		return TraceEvent{}, err
+	}
+	if e.SourceElapsed, err = traceMicroseconds(row[3]); err != nil {
+		return TraceEvent{}, err
+	}
+	if e.Thread, err = traceText(row[4]); err != nil {
+		return TraceEvent{}, err
+	}
+	return e, nil
+}
+
+// Columns of system_traces tables may be null, they are decoded to zero values then.
+
+func traceText(v frame.CqlValue) (string, error) {
+	if v.Value == nil {
+		return "", nil
+	}
+	return v.AsText()
+}
+
+func traceIP(v frame.CqlValue) (net.IP, error) {
+	if v.Value == nil {
+		return nil, nil
+	}
+	return v.AsIP()
+}
+
+func traceMicroseconds(v frame.CqlValue) (time.Duration, error) {
+	if v.Value == nil {
+		return 0, nil
+	}
+	n, err := v.AsInt32()
+	return time.Duration(n) * time.Microsecond, err
+}
+
+func uuidString(u frame.UUID) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
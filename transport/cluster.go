@@ -3,10 +3,12 @@ package transport
 import (
 	"context"
 	"fmt"
+	"math"
 	"net"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kulezi/scylla-go-driver/frame"
@@ -34,6 +36,14 @@ type Cluster struct {
 	reopenControlChan requestChan
 	closeChan         requestChan
 	closed            atomic.Bool
+	onNodeUp          atomic.Value // func(context.Context, *Node)
+	onEvent           atomic.Value // func(frame.Response)
+
+	// pendingEvents are published after the next topology refresh, which applies them.
+	// At most maxPendingEvents are kept, the oldest ones are dropped and counted in droppedEvents.
+	pendingEvents   []frame.Response
+	pendingEventsMu sync.Mutex
+	droppedEvents   atomic.Uint64
 
 	queryInfoCounter atomic.Uint64
 }
@@ -45,6 +55,8 @@ type topology struct {
 	Nodes      []*Node
 	policyInfo policyInfo
 	keyspaces  ksMap
+	// replicaCache holds *ringReplicas by keyspace name.
+	replicaCache sync.Map
 }
 
 type keyspace struct {
@@ -74,13 +86,14 @@ type strategy struct {
 }
 
 // QueryInfo represents data required for host selection policy to create query plan.
-// Token and strategy are only necessary for token aware policies.
+// Token and replicas are only necessary for token aware policies.
 type QueryInfo struct {
 	tokenAware bool
 	token      Token
 	topology   *topology
-	strategy   strategy
+	replicas   *ringReplicas
 	offset     uint64 // For round robin strategies.
+	lwt        bool   // Lightweight transactions are routed to replicas in ring order, ignoring offset.
 }
 
 func (c *Cluster) NewQueryInfo() QueryInfo {
@@ -101,22 +114,119 @@ func (c *Cluster) NewTokenAwareQueryInfo(t Token, ks string) (QueryInfo, error)
 		}
 		ks = c.cfg.Keyspace
 	}
-	if stg, ok := top.keyspaces[ks]; ok {
-		return QueryInfo{
-			tokenAware: true,
-			token:      t,
-			topology:   top,
-			strategy:   stg.strategy,
-			offset:     c.generateOffset(),
-		}, nil
-	} else {
-		var allKs []string
-		for k := range top.keyspaces {
-			allKs = append(allKs, k)
+	k, err := top.keyspace(ks)
+	if err != nil || len(top.policyInfo.ring) == 0 {
+		// Keyspace may have been created after the last topology refresh, fallback to non-token aware query.
+		return c.NewQueryInfo(), nil
+	}
+	replicas, err := top.ringReplicas(ks, k.strategy)
+	if err != nil {
+		// Replicas of custom replication strategies are unknown.
+		return c.NewQueryInfo(), nil
+	}
+	return QueryInfo{
+		tokenAware: true,
+		token:      t,
+		topology:   top,
+		replicas:   replicas,
+		offset:     c.generateOffset(),
+	}, nil
+}
+
+// NewLWTQueryInfo creates token aware query info for lightweight transactions,
+// routing them to the same replicas in the same order reduces Paxos contention.
+func (c *Cluster) NewLWTQueryInfo(t Token, ks string) (QueryInfo, error) {
+	qi, err := c.NewTokenAwareQueryInfo(t, ks)
+	if err != nil {
+		return QueryInfo{}, err
+	}
+	qi.lwt = qi.tokenAware
+	return qi, nil
+}
+
+// Replicas returns replicas of token t in keyspace ks in ring order across all datacenters, primary replica goes first.
+// When ks is empty, the keyspace from ConnConfig is used.
+func (c *Cluster) Replicas(ks string, t Token) ([]*Node, error) {
+	top := c.Topology()
+	k, err := top.keyspace(c.keyspaceName(ks))
+	if err != nil {
+		return nil, err
+	}
+	if len(top.policyInfo.ring) == 0 {
+		return nil, fmt.Errorf("token ring is empty")
+	}
+	return top.replicas(k.strategy, top.policyInfo.ring.tokenLowerBound(t))
+}
+
+// TokenRange is a range of tokens from Start exclusive to End inclusive owned by Replicas.
+type TokenRange struct {
+	Start    Token
+	End      Token
+	Replicas []*Node
+}
+
+// TokenRanges returns ranges between consecutive tokens of the ring together with their replicas in keyspace ks,
+// see Cluster.Replicas. Ranges are sorted and cover the whole ring, the range wrapping around the ring
+// is split at math.MinInt64, so that Start < End holds for every range.
+func (c *Cluster) TokenRanges(ks string) ([]TokenRange, error) {
+	top := c.Topology()
+	k, err := top.keyspace(c.keyspaceName(ks))
+	if err != nil {
+		return nil, err
+	}
+	ring := top.policyInfo.ring
+	if len(ring) == 0 {
+		return nil, fmt.Errorf("token ring is empty")
+	}
+
+	res := make([]TokenRange, 0, len(ring)+1)
+	add := func(start, end Token, i int) error {
+		if start == end {
+			return nil
+		}
+		r, err := top.replicas(k.strategy, i)
+		if err != nil {
+			return err
 		}
-		sort.Strings(allKs)
-		return QueryInfo{}, fmt.Errorf("couldn't find keyspace %q in current topology, known keyspaces are: %s", ks, strings.Join(allKs, ", "))
+		res = append(res, TokenRange{Start: start, End: end, Replicas: r})
+		return nil
+	}
+	// Tokens after the last one in the ring belong to the first one.
+	if err := add(math.MinInt64, ring[0].token, 0); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(ring); i++ {
+		if err := add(ring[i-1].token, ring[i].token, i); err != nil {
+			return nil, err
+		}
+	}
+	if err := add(ring[len(ring)-1].token, math.MaxInt64, 0); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// keyspaceName returns ks or the keyspace from ConnConfig if ks is empty.
+func (c *Cluster) keyspaceName(ks string) string {
+	if ks == "" {
+		return c.cfg.Keyspace
+	}
+	return ks
+}
+
+func (t *topology) keyspace(name string) (keyspace, error) {
+	if name == "" {
+		return keyspace{}, fmt.Errorf("keyspace is not specified")
+	}
+	if k, ok := t.keyspaces[name]; ok {
+		return k, nil
+	}
+	var allKs []string
+	for k := range t.keyspaces {
+		allKs = append(allKs, k)
 	}
+	sort.Strings(allKs)
+	return keyspace{}, fmt.Errorf("couldn't find keyspace %q in current topology, known keyspaces are: %s", name, strings.Join(allKs, ", "))
 }
 
 // TODO overflow and negative modulo.
@@ -210,10 +320,15 @@ func (c *Cluster) refreshTopology(ctx context.Context) error {
 			return err
 		}
 		// If node is present in both maps we can reuse its connection pool.
-		if node, ok := old[n.addr]; ok {
+		node, known := old[n.addr]
+		if known {
 			n.pool = node.pool
+			n.status.Store(node.status.Load())
 		}
 		n.Init(ctx, c.cfg)
+		if !known && n.IsUp() {
+			c.nodeUp(ctx, n)
+		}
 
 		// Every encountered node becomes known host for future use.
 		c.knownHosts[n.addr] = struct{}{}
@@ -235,10 +350,13 @@ func (c *Cluster) refreshTopology(ctx context.Context) error {
 		}
 	}
 
+	// Replicas are computed on demand from the sorted ring, those of the default keyspace right away.
+	sort.Sort(t.policyInfo.ring)
+	t.policyInfo.Preprocess(t)
 	if ks, ok := t.keyspaces[c.cfg.Keyspace]; ok {
-		t.policyInfo.Preprocess(t, ks)
-	} else {
-		t.policyInfo.Preprocess(t, keyspace{})
+		if _, err := t.ringReplicas(c.cfg.Keyspace, ks.strategy); err != nil {
+			c.cfg.Logger.Printf("cluster: default keyspace replicas: %v", err)
+		}
 	}
 
 	c.setTopology(t)
@@ -418,7 +536,7 @@ func parseNetworkStrategy(name strategyClass, stg map[string]string) (strategy,
 	}, nil
 }
 
-// parseTokensFromRow also inserts tokens into ring.
+// parseTokensFromRow also inserts tokens into ring and sets tokens of n.
 func parseTokensFromRow(n *Node, r frame.Row, ring *Ring) error {
 	if tokens, err := r[tokensIndex].AsStringSlice(); err != nil {
 		return err
@@ -431,9 +549,11 @@ func parseTokensFromRow(n *Node, r frame.Row, ring *Ring) error {
 					node:  n,
 					token: Token(v),
 				})
+				n.tokens = append(n.tokens, Token(v))
 			}
 		}
 	}
+	sort.Slice(n.tokens, func(i, j int) bool { return n.tokens[i] < n.tokens[j] })
 	return nil
 }
 
@@ -441,6 +561,11 @@ func (c *Cluster) Topology() *topology {
 	return c.topology.Load().(*topology)
 }
 
+// LocalDC returns the datacenter preferred by the host selection policy, it's empty if the policy isn't DC aware.
+func (c *Cluster) LocalDC() string {
+	return c.Topology().localDC
+}
+
 func (c *Cluster) setTopology(t *topology) {
 	c.topology.Store(t)
 }
@@ -460,7 +585,7 @@ func (c *Cluster) handleEvent(ctx context.Context, r response) {
 	case *StatusChange:
 		c.handleStatusChange(ctx, v)
 	case *SchemaChange:
-		// TODO: add schema change.
+		c.handleSchemaChange(v)
 	default:
 		c.cfg.Logger.Printf("cluster: unsupported event type: %v", r.Response)
 	}
@@ -468,6 +593,7 @@ func (c *Cluster) handleEvent(ctx context.Context, r response) {
 
 func (c *Cluster) handleTopologyChange(v *TopologyChange) {
 	c.cfg.Logger.Printf("cluster: handle topology change: %+#v", v)
+	c.deferEvent(v)
 	c.RequestRefresh()
 }
 
@@ -478,18 +604,109 @@ func (c *Cluster) handleStatusChange(ctx context.Context, v *StatusChange) {
 	if n, ok := m[addr]; ok {
 		switch v.Status {
 		case frame.Up:
-			n.Init(ctx, c.cfg)
+			wasUp := n.IsUp()
+			if n.pool != nil {
+				n.setStatus(statusUP)
+			} else {
+				n.Init(ctx, c.cfg)
+			}
+			if !wasUp && n.IsUp() {
+				c.nodeUp(ctx, n)
+			}
 		case frame.Down:
 			n.setStatus(statusDown)
 		default:
 			c.cfg.Logger.Printf("cluster: status change not supported: %+#v", v)
 		}
+		c.publishEvent(v)
 	} else {
 		c.cfg.Logger.Printf("cluster: unknown node %s received status change: %+#v in topology %v", addr, v, m)
+		c.deferEvent(v)
 		c.RequestRefresh()
 	}
 }
 
+// handleSchemaChange refreshes topology if replication of a keyspace might have changed,
+// changes of other schema elements don't affect the topology.
+func (c *Cluster) handleSchemaChange(v *SchemaChange) {
+	c.cfg.Logger.Printf("cluster: handle schema change: %+#v", v)
+	if v.Target == frame.Keyspace {
+		c.deferEvent(v)
+		c.RequestRefresh()
+	} else {
+		c.publishEvent(v)
+	}
+}
+
+// SetOnEvent registers f to be called with every event after it's applied to the topology.
+// f is called synchronously with handling events, so it mustn't block.
+func (c *Cluster) SetOnEvent(f func(frame.Response)) {
+	c.onEvent.Store(f)
+}
+
+func (c *Cluster) publishEvent(v frame.Response) {
+	if f, ok := c.onEvent.Load().(func(frame.Response)); ok && f != nil {
+		f(v)
+	}
+}
+
+// deferEvent delays publishing v until the next successful topology refresh.
+func (c *Cluster) deferEvent(v frame.Response) {
+	c.pendingEventsMu.Lock()
+	defer c.pendingEventsMu.Unlock()
+	c.pendingEvents = append(c.pendingEvents, v)
+	c.trimPendingEvents()
+}
+
+func (c *Cluster) takePendingEvents() []frame.Response {
+	c.pendingEventsMu.Lock()
+	defer c.pendingEventsMu.Unlock()
+	res := c.pendingEvents
+	c.pendingEvents = nil
+	return res
+}
+
+// restorePendingEvents puts back events taken before a failed refresh, ahead of events deferred in the meantime.
+func (c *Cluster) restorePendingEvents(events []frame.Response) {
+	c.pendingEventsMu.Lock()
+	defer c.pendingEventsMu.Unlock()
+	c.pendingEvents = append(events, c.pendingEvents...)
+	c.trimPendingEvents()
+}
+
+// maxPendingEvents bounds the number of deferred events, while refreshes keep failing
+// every topology or status change of a big cluster would be queued otherwise.
+const maxPendingEvents = 1024
+
+// trimPendingEvents drops the oldest pending events above maxPendingEvents, pendingEventsMu must be held.
+func (c *Cluster) trimPendingEvents() {
+	n := len(c.pendingEvents) - maxPendingEvents
+	if n <= 0 {
+		return
+	}
+	c.pendingEvents = append(c.pendingEvents[:0:0], c.pendingEvents[n:]...)
+	c.droppedEvents.Add(uint64(n))
+	c.cfg.Logger.Printf("cluster: dropped %d pending events, too many events since the last topology refresh", n)
+}
+
+// DroppedEvents returns the number of events that weren't published because too many of them
+// were waiting for a topology refresh.
+func (c *Cluster) DroppedEvents() uint64 {
+	return c.droppedEvents.Load()
+}
+
+// SetOnNodeUp registers f to be called whenever a node comes up or joins the cluster.
+// f is run in a separate goroutine, so it can communicate with the node.
+func (c *Cluster) SetOnNodeUp(f func(context.Context, *Node)) {
+	c.onNodeUp.Store(f)
+}
+
+func (c *Cluster) nodeUp(ctx context.Context, n *Node) {
+	if f, ok := c.onNodeUp.Load().(func(context.Context, *Node)); ok && f != nil {
+		go f(ctx, n)
+	}
+}
+
 const refreshInterval = 60 * time.Second
 
 // loop handles cluster requests.
@@ -520,11 +737,28 @@ const tryRefreshInterval = time.Second
 // tryRefresh refreshes cluster topology.
 // In case of error tries to reopen control connection and tries again.
 func (c *Cluster) tryRefresh(ctx context.Context) {
+	events := c.takePendingEvents()
 	if err := c.refreshTopology(ctx); err != nil {
+		c.restorePendingEvents(events)
 		c.RequestReopenControl()
 		time.AfterFunc(tryRefreshInterval, c.RequestRefresh)
 		c.cfg.Logger.Printf("cluster: refresh topology: %v", err)
+		return
 	}
+
+	for _, v := range events {
+		c.publishEvent(v)
+	}
+	// Refresh requested by events received during the refresh could have been drained.
+	if c.hasPendingEvents() {
+		c.RequestRefresh()
+	}
+}
+
+func (c *Cluster) hasPendingEvents() bool {
+	c.pendingEventsMu.Lock()
+	defer c.pendingEventsMu.Unlock()
+	return len(c.pendingEvents) != 0
 }
 
 const tryReopenControlInterval = time.Second
@@ -0,0 +1,39 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/kulezi/scylla-go-driver/frame"
+	. "github.com/kulezi/scylla-go-driver/frame/response"
+)
+
+func TestClusterPendingEventsBound(t *testing.T) {
+	t.Parallel()
+	c := Cluster{cfg: ConnConfig{Logger: DefaultLogger{}}}
+
+	const extra = 10
+	for i := 0; i < maxPendingEvents+extra; i++ {
+		c.deferEvent(&StatusChange{Status: frame.Up, Address: frame.Inet{Port: frame.Int(i)}})
+	}
+	events := c.takePendingEvents()
+	if len(events) != maxPendingEvents {
+		t.Fatalf("expected %d pending events, got %d", maxPendingEvents, len(events))
+	}
+	if port := events[0].(*StatusChange).Address.Port; port != extra {
+		t.Fatalf("expected oldest events to be dropped, first event has port %d", port)
+	}
+	if v := c.DroppedEvents(); v != extra {
+		t.Fatalf("expected %d dropped events, got %d", extra, v)
+	}
+
+	// Events taken before a failed refresh are put back ahead of new ones, the oldest are still dropped.
+	c.deferEvent(&StatusChange{Status: frame.Down})
+	c.restorePendingEvents(events)
+	if v := c.DroppedEvents(); v != extra+1 {
+		t.Fatalf("expected %d dropped events, got %d", extra+1, v)
+	}
+	events = c.takePendingEvents()
+	if last := events[len(events)-1].(*StatusChange); last.Status != frame.Down {
+		t.Fatalf("expected newest event to be kept, got %+v", last)
+	}
+}
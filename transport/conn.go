@@ -26,7 +26,11 @@ import (
 type response struct {
 	frame.Header
 	frame.Response
-	Err error
+	// TracingID is set if the request was traced.
+	TracingID     frame.UUID
+	Warnings      frame.StringList
+	CustomPayload frame.BytesMap
+	Err           error
 }
 
 type ResponseHandler chan response
@@ -36,6 +40,7 @@ type request struct {
 	StreamID        frame.StreamID
 	Compress        bool
 	Tracing         bool
+	CustomPayload   frame.BytesMap
 	ResponseHandler ResponseHandler
 
 	ctx context.Context // nolint:containedctx // cancelling sending request can't be done without it.
@@ -53,6 +58,9 @@ func (e *skippedError) Unwrap() error {
 	return e.err
 }
 
+var ErrRequestTimeout = fmt.Errorf("request timeout")
+
+// _connCloseRequest is the only request without a body.
 var _connCloseRequest = request{ctx: context.Background()}
 
 type stats struct {
@@ -98,7 +106,7 @@ func (c *connWriter) loop(ctx context.Context) {
 
 		for i := 0; i < size; i++ {
 			r := <-c.requestCh
-			if r == _connCloseRequest {
+			if r.Request == nil {
 				return
 			}
 			c.stats.inQueue.Dec()
@@ -131,7 +139,16 @@ func (c *connWriter) send(ctx context.Context, r request) error {
 		StreamID: r.StreamID,
 		OpCode:   r.OpCode(),
 	}
+	if r.Tracing {
+		h.Flags |= frame.Tracing
+	}
+	if len(r.CustomPayload) != 0 {
+		h.Flags |= frame.CustomPayload
+	}
 	h.WriteTo(&c.buf)
+	if len(r.CustomPayload) != 0 {
+		c.buf.WriteBytesMap(r.CustomPayload)
+	}
 	r.WriteTo(&c.buf)
 
 	// Update length in header
@@ -171,10 +188,13 @@ type connReader struct {
 	connString  func() string
 	connClose   func()
 
-	h      map[frame.StreamID]ResponseHandler
-	s      streamIDAllocator
-	closed bool
-	mu     sync.Mutex // mu guards h, s and closed
+	// Streams of requests that stopped waiting for the response are kept in h with nil handlers,
+	// they are released when the late response arrives.
+	h        map[frame.StreamID]ResponseHandler
+	s        streamIDAllocator
+	orphaned int
+	closed   bool
+	mu       sync.Mutex // mu guards h, s, orphaned and closed
 
 	logger Logger
 }
@@ -196,21 +216,46 @@ func (c *connReader) setHandler(h ResponseHandler) (frame.StreamID, error) {
 	return streamID, err
 }
 
-// handler free given streamID and return corresponding handler.
-func (c *connReader) handler(streamID frame.StreamID) ResponseHandler {
+// handler frees given streamID and returns corresponding handler,
+// ok is false if the stream wasn't allocated and h is nil if the stream was orphaned.
+func (c *connReader) handler(streamID frame.StreamID) (h ResponseHandler, ok bool) {
 	c.mu.Lock()
-	h := c.h[streamID]
-	c.s.Free(streamID)
-	delete(c.h, streamID)
-	c.mu.Unlock()
-	return h
+	defer c.mu.Unlock()
+	if h, ok = c.h[streamID]; ok {
+		c.release(streamID, h)
+	}
+	return h, ok
 }
 
 func (c *connReader) freeStream(streamID frame.StreamID) {
 	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h, ok := c.h[streamID]; ok {
+		c.release(streamID, h)
+	}
+}
+
+// release must be called with mu held.
+func (c *connReader) release(streamID frame.StreamID, h ResponseHandler) {
+	if h == nil {
+		c.orphaned--
+	}
 	c.s.Free(streamID)
 	delete(c.h, streamID)
-	c.mu.Unlock()
+}
+
+// orphan marks stream of a request that stopped waiting for the response,
+// the stream can't be reused until the response arrives as it would be delivered to a wrong request.
+// It returns the number of orphaned streams on the connection.
+func (c *connReader) orphan(streamID frame.StreamID, h ResponseHandler) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Response might have arrived in the meantime and the stream could have been allocated again.
+	if c.h[streamID] == h {
+		c.h[streamID] = nil
+		c.orphaned++
+	}
+	return c.orphaned
 }
 
 // loop terminates when its connection gets closed by the pool, especially when session context is done.
@@ -234,9 +279,9 @@ func (c *connReader) loop(ctx context.Context) {
 
 		c.stats.inFlight.Dec()
 
-		if h := c.handler(resp.StreamID); h != nil {
+		if h, ok := c.handler(resp.StreamID); h != nil {
 			h <- resp
-		} else {
+		} else if !ok {
 			c.logger.Printf("%s received unknown stream ID %d, closing connection", c.connString(), resp.StreamID)
 			c.connClose()
 			c.drainHandlers()
@@ -276,6 +321,10 @@ func (c *connReader) recv() response {
 		}
 	}
 
+	// Optional fields precede the body of the response.
+	opt := frame.ParseMsgOptionalFields(&c.buf, r.Header.Flags)
+	r.TracingID, r.Warnings, r.CustomPayload = opt.TracingID, opt.Warnings, opt.CustomPayload
+
 	r.Response = c.parse(r.Header.OpCode)
 	if r.Response == nil {
 		r.Err = fmt.Errorf("response type not supported")
@@ -293,7 +342,9 @@ func (c *connReader) drainHandlers() {
 	c.mu.Lock()
 	c.closed = true
 	for _, h := range c.h {
-		h <- response{Err: fmt.Errorf("%s closed", c.connString())}
+		if h != nil {
+			h <- response{Err: fmt.Errorf("%s closed", c.connString())}
+		}
 	}
 	c.mu.Unlock()
 }
@@ -332,6 +383,9 @@ type Conn struct {
 	stats     *stats
 	closeOnce sync.Once
 	onClose   func(conn *Conn)
+
+	// lwtFlagMask marks lightweight transactions in PREPARED metadata flags, 0 if not negotiated.
+	lwtFlagMask int
 }
 
 type ConnConfig struct {
@@ -339,7 +393,15 @@ type ConnConfig struct {
 	Password   string
 	Keyspace   string
 	TCPNoDelay bool
-	Timeout    time.Duration
+	// Timeout limits time of dialing a connection.
+	Timeout time.Duration
+	// RequestTimeout limits time of waiting for a response, it can be overridden by Statement.Timeout.
+	// If less or equal to 0, requests wait for the response until their context is done.
+	RequestTimeout time.Duration
+	// MaxOrphanedStreams is the number of streams abandoned by timed out or cancelled requests
+	// after which the connection is closed and replaced by the pool.
+	// If less or equal to 0, connections are never closed because of orphaned streams.
+	MaxOrphanedStreams int
 
 	// If not nil, all connections will use TLS according to TLSConfig,
 	// please note that the default port (9042) may not support TLS.
@@ -364,6 +426,8 @@ func DefaultConnConfig(keyspace string) ConnConfig {
 		Keyspace:              keyspace,
 		TCPNoDelay:            true,
 		Timeout:               500 * time.Millisecond,
+		RequestTimeout:        30 * time.Second,
+		MaxOrphanedStreams:    maxStreamID / 32,
 		DefaultConsistency:    frame.LOCALQUORUM,
 		DefaultPort:           "9042",
 		ComprBufferSize:       comprBufferSize,
@@ -551,12 +615,17 @@ func (c *Conn) init(ctx context.Context) error {
 	if s, err := c.Supported(ctx); err != nil {
 		return fmt.Errorf("supported: %w", err)
 	} else {
-		c.event.Shard = s.ScyllaSupported().Shard
+		ss := s.ScyllaSupported()
+		c.event.Shard = ss.Shard
+		c.lwtFlagMask = ss.LwtFlagMask
 	}
 	opts := frame.StartupOptions{"CQL_VERSION": cqlVersion}
 	if c.cfg.Compression != "" {
 		opts["COMPRESSION"] = string(c.cfg.Compression)
 	}
+	if c.lwtFlagMask != 0 {
+		opts[ScyllaLWTAddMetadataMark] = fmt.Sprintf("%s=%d", ScyllaLWTOptMetaBitMask, c.lwtFlagMask)
+	}
 	if err := c.Startup(ctx, opts); err != nil {
 		return fmt.Errorf("startup: %w", err)
 	}
@@ -570,28 +639,28 @@ func (c *Conn) init(ctx context.Context) error {
 }
 
 func (c *Conn) Supported(ctx context.Context) (*Supported, error) {
-	res, err := c.sendRequest(ctx, &Options{}, false, false)
+	res, err := c.sendRequest(ctx, &Options{}, requestOptions{})
 	if err != nil {
 		return nil, err
 	}
-	if v, ok := res.(*Supported); ok {
+	if v, ok := res.Response.(*Supported); ok {
 		return v, nil
 	}
-	return nil, responseAsError(res)
+	return nil, responseAsError(res.Response)
 }
 
 func (c *Conn) Startup(ctx context.Context, options frame.StartupOptions) error {
-	res, err := c.sendRequest(ctx, &Startup{Options: options}, false, false)
+	res, err := c.sendRequest(ctx, &Startup{Options: options}, requestOptions{})
 	if err != nil {
 		return err
 	}
-	switch v := res.(type) {
+	switch v := res.Response.(type) {
 	case *Ready:
 		return nil
 	case *Authenticate:
 		return c.AuthResponse(ctx, v)
 	default:
-		return responseAsError(res)
+		return responseAsError(res.Response)
 	}
 }
 
@@ -610,11 +679,11 @@ func (c *Conn) AuthResponse(ctx context.Context, a *Authenticate) error {
 		Username: c.cfg.Username,
 		Password: c.cfg.Password,
 	}
-	res, err := c.sendRequest(ctx, &req, false, false)
+	res, err := c.sendRequest(ctx, &req, requestOptions{})
 	if err != nil {
 		return fmt.Errorf("can't send auth response: %w", err)
 	}
-	switch v := res.(type) {
+	switch v := res.Response.(type) {
 	case *AuthSuccess:
 		return nil
 	case *AuthChallenge:
@@ -631,7 +700,7 @@ func (c *Conn) UseKeyspace(ctx context.Context, ks string) error {
 
 func (c *Conn) Query(ctx context.Context, s Statement, pagingState frame.Bytes) (QueryResult, error) {
 	req := makeQuery(s, pagingState)
-	res, err := c.sendRequest(ctx, &req, s.Compression, s.Tracing)
+	res, err := c.sendRequest(ctx, &req, s.requestOptions())
 	if err != nil {
 		return QueryResult{}, err
 	}
@@ -641,29 +710,40 @@ func (c *Conn) Query(ctx context.Context, s Statement, pagingState frame.Bytes)
 
 func (c *Conn) Prepare(ctx context.Context, s Statement) (Statement, error) {
 	req := Prepare{Query: s.Content}
-	res, err := c.sendRequest(ctx, &req, false, false)
+	res, err := c.sendRequest(ctx, &req, requestOptions{})
 	if err != nil {
 		return Statement{}, err
 	}
 
-	if v, ok := res.(*PreparedResult); ok {
+	if v, ok := res.Response.(*PreparedResult); ok {
 		s.ID = v.ID
 		s.Values = make([]frame.Value, len(v.Metadata.Columns))
 		s.PkIndexes = v.Metadata.PkIndexes
 		s.PkCnt = v.Metadata.PkCnt
+		s.BindMarkers = v.Metadata.Columns
+		s.Keyspace = preparedKeyspace(&v.Metadata)
 		s.Metadata = &v.ResultMetadata
+		s.LWT = c.lwtFlagMask != 0 && uint32(v.Metadata.Flags)&uint32(c.lwtFlagMask) != 0
 		for i := range s.Values {
 			s.Values[i].Type = &v.Metadata.Columns[i].Type
 		}
 		return s, nil
 	}
 
-	return Statement{}, responseAsError(res)
+	return Statement{}, responseAsError(res.Response)
+}
+
+// preparedKeyspace returns keyspace of the bind markers, it's sent either once for all of them or with each marker.
+func preparedKeyspace(m *frame.PreparedMetadata) string {
+	if m.GlobalKeyspace != "" || len(m.Columns) == 0 {
+		return m.GlobalKeyspace
+	}
+	return m.Columns[0].Keyspace
 }
 
 func (c *Conn) Execute(ctx context.Context, s Statement, pagingState frame.Bytes) (QueryResult, error) {
 	req := makeExecute(s, pagingState)
-	res, err := c.sendRequest(ctx, &req, s.Compression, s.Tracing)
+	res, err := c.sendRequest(ctx, &req, s.requestOptions())
 	if err != nil {
 		return QueryResult{}, err
 	}
@@ -671,17 +751,30 @@ func (c *Conn) Execute(ctx context.Context, s Statement, pagingState frame.Bytes
 	return MakeQueryResult(res, s.Metadata)
 }
 
+func (c *Conn) Batch(ctx context.Context, b BatchStatement) (QueryResult, error) {
+	if err := b.checkNames(); err != nil {
+		return QueryResult{}, err
+	}
+	req := makeBatch(b)
+	res, err := c.sendRequest(ctx, &req, b.requestOptions())
+	if err != nil {
+		return QueryResult{}, err
+	}
+
+	return MakeQueryResult(res, nil)
+}
+
 func (c *Conn) RegisterEventHandler(ctx context.Context, h func(context.Context, response), e ...frame.EventType) error {
 	c.r.handleEvent = h
 	req := Register{EventTypes: e}
-	res, err := c.sendRequest(ctx, &req, false, false)
+	res, err := c.sendRequest(ctx, &req, requestOptions{})
 	if err != nil {
 		return err
 	}
-	if _, ok := res.(*Ready); ok {
+	if _, ok := res.Response.(*Ready); ok {
 		return nil
 	}
-	return responseAsError(res)
+	return responseAsError(res.Response)
 }
 
 func MakeResponseHandler() ResponseHandler {
@@ -697,22 +790,33 @@ func MakeResponseHandlerWithError(err error) ResponseHandler {
 	return h
 }
 
-func (c *Conn) sendRequest(ctx context.Context, req frame.Request, compress, tracing bool) (frame.Response, error) {
+// requestOptions are settings of a single request that don't belong to its body.
+type requestOptions struct {
+	compress      bool
+	tracing       bool
+	timeout       time.Duration
+	customPayload frame.BytesMap
+}
+
+// sendRequest waits for the response until ctx is done or timeout passes, if timeout is less or equal to 0
+// ConnConfig.RequestTimeout is used. Stream of a request that stopped waiting is orphaned.
+func (c *Conn) sendRequest(ctx context.Context, req frame.Request, opts requestOptions) (response, error) {
 	if err := c.sendController(ctx); err != nil {
-		return nil, fmt.Errorf("request skipped, %w", err)
+		return response{}, fmt.Errorf("request skipped, %w", err)
 	}
 	h := MakeResponseHandler()
 
 	streamID, err := c.r.setHandler(h)
 	if err != nil {
-		return nil, fmt.Errorf("set handler: %w", err)
+		return response{}, fmt.Errorf("set handler: %w", err)
 	}
 
 	r := request{
 		Request:         req,
 		StreamID:        streamID,
-		Compress:        compress,
-		Tracing:         tracing,
+		Compress:        opts.compress,
+		Tracing:         opts.tracing,
+		CustomPayload:   opts.customPayload,
 		ResponseHandler: h,
 		ctx:             ctx,
 	}
@@ -722,15 +826,37 @@ func (c *Conn) sendRequest(ctx context.Context, req frame.Request, compress, tra
 	// adding a grace period before terminating writeLoop or counting active streams.
 	c.w.submit(r)
 
+	timeout := opts.timeout
+	if timeout <= 0 {
+		timeout = c.cfg.RequestTimeout
+	}
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
 	select {
 	case resp := <-h:
-		return resp.Response, resp.Err
+		return resp, resp.Err
 	case <-ctx.Done():
-		return nil, fmt.Errorf("no response, %w", ctx.Err())
+		c.orphan(streamID, h)
+		return response{}, fmt.Errorf("no response, %w", ctx.Err())
+	case <-timeoutCh:
+		c.orphan(streamID, h)
+		return response{}, fmt.Errorf("%s no response after %v: %w", c, timeout, ErrRequestTimeout)
 	}
 }
 
-func (c *Conn) asyncSendRequest(ctx context.Context, req frame.Request, compress, tracing bool, h ResponseHandler) {
+func (c *Conn) orphan(streamID frame.StreamID, h ResponseHandler) {
+	if n := c.r.orphan(streamID, h); c.cfg.MaxOrphanedStreams > 0 && n > c.cfg.MaxOrphanedStreams {
+		c.cfg.Logger.Printf("%s too many orphaned streams (%d), closing connection", c, n)
+		c.Close()
+	}
+}
+
+func (c *Conn) asyncSendRequest(ctx context.Context, req frame.Request, opts requestOptions, h ResponseHandler) {
 control:
 	if err := c.sendController(ctx); err != nil {
 		h <- response{Err: fmt.Errorf("no response, %v", err)}
@@ -750,8 +876,9 @@ control:
 	r := request{
 		Request:         req,
 		StreamID:        streamID,
-		Compress:        compress,
-		Tracing:         tracing,
+		Compress:        opts.compress,
+		Tracing:         opts.tracing,
+		CustomPayload:   opts.customPayload,
 		ResponseHandler: h,
 		ctx:             ctx,
 	}
@@ -784,12 +911,17 @@ func (c *Conn) sendController(ctx context.Context) error {
 
 func (c *Conn) AsyncQuery(ctx context.Context, s Statement, pagingState frame.Bytes, h ResponseHandler) {
 	req := makeQuery(s, pagingState)
-	c.asyncSendRequest(ctx, &req, s.Compression, s.Tracing, h)
+	c.asyncSendRequest(ctx, &req, s.requestOptions(), h)
 }
 
 func (c *Conn) AsyncExecute(ctx context.Context, s Statement, pagingState frame.Bytes, h ResponseHandler) {
 	req := makeExecute(s, pagingState)
-	c.asyncSendRequest(ctx, &req, s.Compression, s.Tracing, h)
+	c.asyncSendRequest(ctx, &req, s.requestOptions(), h)
+}
+
+func (c *Conn) AsyncBatch(ctx context.Context, b BatchStatement, h ResponseHandler) {
+	req := makeBatch(b)
+	c.asyncSendRequest(ctx, &req, b.requestOptions(), h)
 }
 
 func (c *Conn) Waiting() int {
@@ -1,7 +1,16 @@
 package transport
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kulezi/scylla-go-driver/frame"
+	. "github.com/kulezi/scylla-go-driver/frame/request"
+	. "github.com/kulezi/scylla-go-driver/frame/response"
 )
 
 func TestPortParsing(t *testing.T) {
@@ -49,3 +58,131 @@ func TestPortParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestConnReaderOrphanedStreams(t *testing.T) {
+	t.Parallel()
+	c := connReader{
+		h:          make(map[frame.StreamID]ResponseHandler),
+		connString: func() string { return "test" },
+	}
+
+	h := MakeResponseHandler()
+	id, err := c.setHandler(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := c.orphan(id, h); n != 1 {
+		t.Fatalf("expected 1 orphaned stream, got %d", n)
+	}
+
+	// Orphaned stream can't be reused until the late response arrives.
+	other := MakeResponseHandler()
+	otherID, err := c.setHandler(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherID == id {
+		t.Fatal("orphaned stream was reused")
+	}
+
+	if h, ok := c.handler(id); !ok || h != nil {
+		t.Fatalf("expected orphaned stream, got %v %v", h, ok)
+	}
+	if c.orphaned != 0 {
+		t.Fatalf("expected no orphaned streams, got %d", c.orphaned)
+	}
+
+	// Orphaning a stream that already received its response is a no-op, even if the stream is allocated again.
+	if h, ok := c.handler(otherID); !ok || h != other {
+		t.Fatalf("expected handler of the stream, got %v %v", h, ok)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.setHandler(MakeResponseHandler()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := c.h[otherID]; !ok {
+		t.Fatal("expected stream to be allocated again")
+	}
+	if n := c.orphan(otherID, other); n != 0 {
+		t.Fatalf("expected no orphaned streams, got %d", n)
+	}
+}
+
+func TestConnReaderRecvOptionalFields(t *testing.T) {
+	t.Parallel()
+	tracingID := frame.UUID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	warnings := frame.StringList{"Read 1000 live rows and 5000 tombstone cells"}
+	payload := frame.BytesMap{"key": frame.Bytes("value")}
+
+	var body frame.Buffer
+	body.WriteUUID(tracingID)
+	body.WriteStringList(warnings)
+	body.WriteBytesMap(payload)
+	body.WriteInt(frame.Int(VoidKind))
+
+	var buf frame.Buffer
+	frame.Header{
+		Version:  0x80 | frame.CQLv4,
+		Flags:    frame.Tracing | frame.Warning | frame.CustomPayload,
+		StreamID: 1,
+		OpCode:   frame.OpResult,
+		Length:   frame.Int(len(body.Bytes())),
+	}.WriteTo(&buf)
+	buf.Write(body.Bytes())
+
+	c := connReader{
+		conn: io.LimitedReader{R: bufio.NewReader(bytes.NewReader(buf.Bytes()))},
+	}
+	c.bufw = frame.BufferWriter(&c.buf)
+	resp := c.recv()
+	if resp.Err != nil {
+		t.Fatal(resp.Err)
+	}
+	if _, ok := resp.Response.(*VoidResult); !ok {
+		t.Fatalf("expected void result, got %T", resp.Response)
+	}
+	if resp.TracingID != tracingID {
+		t.Fatalf("expected tracing ID %v, got %v", tracingID, resp.TracingID)
+	}
+	if diff := cmp.Diff(warnings, resp.Warnings); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff(payload, resp.CustomPayload); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestConnWriterSendCustomPayload(t *testing.T) {
+	t.Parallel()
+	payload := frame.BytesMap{"key": frame.Bytes("value")}
+
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	c := connWriter{conn: w}
+	r := request{
+		Request:       &Options{},
+		StreamID:      1,
+		CustomPayload: payload,
+		ctx:           context.Background(),
+	}
+	if err := c.send(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b frame.Buffer
+	b.Write(out.Bytes())
+	h := frame.ParseHeader(&b)
+	if h.Flags&frame.CustomPayload == 0 {
+		t.Fatal("expected custom payload flag in the header")
+	}
+	if diff := cmp.Diff(payload, b.ReadBytesMap()); diff != "" {
+		t.Fatal(diff)
+	}
+	if err := b.Error(); err != nil {
+		t.Fatal(err)
+	}
+}
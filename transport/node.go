@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"slices"
 
 	"github.com/kulezi/scylla-go-driver/frame"
 	"go.uber.org/atomic"
@@ -23,6 +24,39 @@ type Node struct {
 	rack       string
 	pool       *ConnPool
 	status     nodeStatus
+	// tokens owned by the node in ascending order.
+	tokens []Token
+}
+
+// NodeInfo is a snapshot of what the driver knows about a node.
+type NodeInfo struct {
+	Addr       string
+	Datacenter string
+	Rack       string
+	HostID     frame.UUID
+	Up         bool
+	// Shards is the number of shards of the node, 0 if the driver never connected to it.
+	Shards int
+	// OpenConns holds the number of open connections to each shard.
+	OpenConns []int
+	// Tokens owned by the node in ascending order.
+	Tokens []Token
+}
+
+func (n *Node) Info() NodeInfo {
+	v := NodeInfo{
+		Addr:       n.addr,
+		Datacenter: n.datacenter,
+		Rack:       n.rack,
+		HostID:     n.hostID,
+		Up:         n.IsUp(),
+		Tokens:     slices.Clone(n.tokens),
+	}
+	if n.pool != nil {
+		v.Shards = n.pool.nrShards
+		v.OpenConns = n.pool.openConns()
+	}
+	return v
 }
 
 func (n *Node) IsUp() bool {
@@ -34,15 +68,19 @@ func (n *Node) setStatus(v bool) {
 }
 
 func (n *Node) Init(ctx context.Context, cfg ConnConfig) {
-	if n.pool == nil {
-		var err error
-		n.pool, err = NewConnPool(ctx, n.addr, cfg)
-		if err == nil {
-			n.setStatus(statusUP)
-		} else {
-			log.Printf("couldn't create a connection pool to node %v: %v\nsetting node status to DOWN", n, err)
-			n.setStatus(statusDown)
-		}
+	if n.pool != nil {
+		// Pool refiller keeps reconnecting to nodes that went down, so the pool can be reused.
+		// Status of the node is left as is, it's changed only by status change events.
+		return
+	}
+
+	var err error
+	n.pool, err = NewConnPool(ctx, n.addr, cfg)
+	if err == nil {
+		n.setStatus(statusUP)
+	} else {
+		log.Printf("couldn't create a connection pool to node %v: %v\nsetting node status to DOWN", n, err)
+		n.setStatus(statusDown)
 	}
 }
 
@@ -60,6 +98,24 @@ func (n *Node) LeastBusyConn() (*Conn, error) {
 
 	return n.pool.LeastBusyConn()
 }
+
+// TokenConn returns connection to the shard owning token t, see ConnPool.Conn.
+func (n *Node) TokenConn(t Token) (*Conn, error) {
+	if !n.IsUp() {
+		return nil, fmt.Errorf("node %v is down", n)
+	}
+	return n.pool.Conn(t)
+}
+
+// ShardInfo returns sharding parameters of the node, Shard is not set.
+// It reports false if there was no connection to the node yet.
+func (n *Node) ShardInfo() (ShardInfo, bool) {
+	if n.pool == nil {
+		return ShardInfo{}, false
+	}
+	return n.pool.shardInfo(), true
+}
+
 func (n *Node) Conn(qi QueryInfo) (*Conn, error) {
 	if !n.IsUp() {
 		return nil, fmt.Errorf("node %v is down", n)
@@ -112,10 +168,8 @@ func (n *Node) FetchSchemaVersion(ctx context.Context) (frame.UUID, error) {
 }
 
 type RingEntry struct {
-	node           *Node
-	token          Token
-	localReplicas  []*Node
-	remoteReplicas []*Node
+	node  *Node
+	token Token
 }
 
 func (r RingEntry) Less(i RingEntry) bool {
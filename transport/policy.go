@@ -1,8 +1,7 @@
 package transport
 
 import (
-	"log"
-	"sort"
+	"fmt"
 )
 
 // HostSelectionPolicy decides which node the query should be routed to.
@@ -20,42 +19,29 @@ func NewTokenAwarePolicy(localDC string) *TokenAwarePolicy {
 }
 
 func (p *TokenAwarePolicy) Node(qi QueryInfo, offset int) *Node {
-	if p.localDC == "" {
-		var replicas []*Node
-		pi := qi.topology.policyInfo
-		if qi.tokenAware {
-			pos := pi.ring.tokenLowerBound(qi.token)
-			replicas = pi.ring[pos].localReplicas
-		} else {
-			// Fallback to round robin on all nodes.
-			replicas = pi.localNodes
-		}
-
-		if offset >= len(replicas) {
-			return nil
-		}
-
-		idx := (qi.offset + uint64(offset)) % uint64(len(replicas))
-		return replicas[idx]
+	start := qi.offset
+	if qi.lwt {
+		// Primary replica goes first, so that concurrent transactions on the same partition use the same coordinator.
+		start = 0
 	}
 
 	var local, remote []*Node
 	pi := qi.topology.policyInfo
 	if qi.tokenAware {
 		pos := pi.ring.tokenLowerBound(qi.token)
-		local = pi.ring[pos].localReplicas
-		remote = pi.ring[pos].remoteReplicas
+		local = qi.replicas.local[pos]
+		remote = qi.replicas.remote[pos]
 	} else {
-		// Fallback to DC aware round robin on all nodes.
+		// Fallback to round robin on all nodes, DC aware if local datacenter is set.
 		local = pi.localNodes
 		remote = pi.remoteNodes
 	}
 
 	if offset < len(local) {
-		idx := (qi.offset + uint64(offset)) % uint64(len(local))
+		idx := (start + uint64(offset)) % uint64(len(local))
 		return local[idx]
 	} else if offset < len(local)+len(remote) {
-		idx := (qi.offset + uint64(offset) - uint64(len(local))) % uint64(len(remote))
+		idx := (start + uint64(offset) - uint64(len(local))) % uint64(len(remote))
 		return remote[idx]
 	}
 
@@ -69,56 +55,87 @@ type policyInfo struct {
 	remoteNodes []*Node
 }
 
-func (pi *policyInfo) Preprocess(t *topology, ks keyspace) {
-	switch ks.strategy.class {
-	case simpleStrategy, localStrategy:
-		pi.preprocessSimpleStrategy(t, ks.strategy)
-	case networkTopologyStrategy:
-		pi.preprocessNetworkTopologyStrategy(t, ks.strategy)
-	default:
-		log.Println("policyInfo: unknown strategy, defaulting to round robin")
-		if t.localDC == "" {
-			pi.preprocessRoundRobinStrategy(t)
-		} else {
-			pi.preprocessDCAwareRoundRobinStrategy(t)
-		}
+// Preprocess prepares nodes for round robin, replicas of tokens are computed per keyspace by topology.ringReplicas.
+func (pi *policyInfo) Preprocess(t *topology) {
+	if t.localDC == "" {
+		pi.preprocessRoundRobinStrategy(t)
+	} else {
+		pi.preprocessDCAwareRoundRobinStrategy(t)
 	}
 }
 
-func (pi *policyInfo) preprocessSimpleStrategy(t *topology, stg strategy) {
-	pi.localNodes = t.Nodes
-	sort.Sort(pi.ring)
+// ringReplicas holds replicas of every token of the ring in a keyspace, in the order of the ring walk.
+// Replicas from the local datacenter are kept apart from the remote ones, without local datacenter all are local.
+type ringReplicas struct {
+	local  [][]*Node
+	remote [][]*Node
+}
+
+// ringReplicas returns replicas of the ring in keyspace ks with replication strategy stg,
+// they are computed on first use and cached for the lifetime of the topology.
+func (t *topology) ringReplicas(ks string, stg strategy) (*ringReplicas, error) {
+	if v, ok := t.replicaCache.Load(ks); ok {
+		return v.(*ringReplicas), nil
+	}
+
+	rr := &ringReplicas{
+		local:  make([][]*Node, len(t.policyInfo.ring)),
+		remote: make([][]*Node, len(t.policyInfo.ring)),
+	}
+	// Consecutive tokens often have the same replicas, trie lets them share the slices.
 	trie := trieRoot()
-	for i := range pi.ring {
-		rit := replicaIter{
-			ring:    pi.ring,
-			offset:  i,
-			fetched: 0,
+	for i := range t.policyInfo.ring {
+		replicas, err := t.replicas(stg, i)
+		if err != nil {
+			return nil, err
 		}
-
-		filter := func(n *Node, res []*Node) bool {
-			for _, v := range res {
-				if n.hostID == v.hostID {
-					return false
-				}
+		local := &trie
+		remote := &trie
+		for _, n := range replicas {
+			if t.localDC == "" || n.datacenter == t.localDC {
+				local = local.Next(n)
+			} else {
+				remote = remote.Next(n)
 			}
-
-			return true
 		}
+		rr.local[i] = local.Path()
+		rr.remote[i] = remote.Path()
+	}
 
-		cur := &trie
-		for len(cur.Path()) < int(stg.rf) {
-			n := rit.Next()
-			if n == nil {
-				break
-			}
+	v, _ := t.replicaCache.LoadOrStore(ks, rr)
+	return v.(*ringReplicas), nil
+}
+
+// simpleStrategyReplicas returns replicas of the i-th token of sorted ring, primary replica goes first.
+func simpleStrategyReplicas(ring Ring, stg strategy, i int) []*Node {
+	rit := replicaIter{
+		ring:    ring,
+		offset:  i,
+		fetched: 0,
+	}
 
-			if filter(n, cur.Path()) {
-				cur = cur.Next(n)
+	filter := func(n *Node, res []*Node) bool {
+		for _, v := range res {
+			if n.hostID == v.hostID {
+				return false
 			}
 		}
-		pi.ring[i].localReplicas = cur.Path()
+
+		return true
+	}
+
+	res := make([]*Node, 0, stg.rf)
+	for len(res) < int(stg.rf) {
+		n := rit.Next()
+		if n == nil {
+			break
+		}
+
+		if filter(n, res) {
+			res = append(res, n)
+		}
 	}
+	return res
 }
 
 func (pi *policyInfo) preprocessRoundRobinStrategy(t *topology) {
@@ -138,74 +155,73 @@ func (pi *policyInfo) preprocessDCAwareRoundRobinStrategy(t *topology) {
 	}
 }
 
-func (pi *policyInfo) preprocessNetworkTopologyStrategy(t *topology, stg strategy) {
-	sort.Sort(pi.ring)
-	trie := trieRoot()
-	for i := range pi.ring {
-		rit := replicaIter{
-			ring:    pi.ring,
-			offset:  i,
-			fetched: 0,
-		}
-		desiredCnt := 0
-		// repeats store the amount of nodes from the same rack that we can take in given DC.
-		repeats := make(map[string]int, len(stg.dcRF))
-		for k, v := range stg.dcRF {
-			desiredCnt += int(v)
-			repeats[k] = int(v) - t.dcRacks[k]
-		}
+// networkTopologyStrategyReplicas returns replicas of the i-th token of sorted ring in all datacenters,
+// in the order of the ring walk. Nodes from already used racks are taken only if there are not enough racks.
+func networkTopologyStrategyReplicas(ring Ring, dcRacks dcRacksMap, stg strategy, i int) []*Node {
+	rit := replicaIter{
+		ring:    ring,
+		offset:  i,
+		fetched: 0,
+	}
+	desiredCnt := 0
+	// repeats store the amount of nodes from the same rack that we can take in given DC.
+	repeats := make(map[string]int, len(stg.dcRF))
+	for k, v := range stg.dcRF {
+		desiredCnt += int(v)
+		repeats[k] = int(v) - dcRacks[k]
+	}
 
-		filter := func(n *Node, res []*Node) bool {
-			rf := stg.dcRF[n.datacenter]
-			fromDC := 0
-			fromRack := 0
-			for _, v := range res {
-				if n.addr == v.addr {
-					return false
-				}
-				if n.datacenter == v.datacenter {
-					fromDC++
-					if n.rack == v.rack {
-						fromRack++
-					}
-				}
+	filter := func(n *Node, res []*Node) bool {
+		rf := stg.dcRF[n.datacenter]
+		fromDC := 0
+		fromRack := 0
+		for _, v := range res {
+			if n.addr == v.addr {
+				return false
 			}
-
-			if fromDC < int(rf) {
-				if fromRack == 0 {
-					return true
-				}
-				if repeats[n.datacenter] > 0 {
-					repeats[n.datacenter]--
-					return true
+			if n.datacenter == v.datacenter {
+				fromDC++
+				if n.rack == v.rack {
+					fromRack++
 				}
 			}
-			return false
 		}
 
-		plan := make([]*Node, 0, desiredCnt)
-		for len(plan) < desiredCnt {
-			n := rit.Next()
-			if n == nil {
-				break
+		if fromDC < int(rf) {
+			if fromRack == 0 {
+				return true
 			}
-
-			if filter(n, plan) {
-				plan = append(plan, n)
+			if repeats[n.datacenter] > 0 {
+				repeats[n.datacenter]--
+				return true
 			}
 		}
+		return false
+	}
 
-		local := &trie
-		remote := &trie
-		for _, n := range plan {
-			if n.datacenter == t.localDC {
-				local = local.Next(n)
-			} else {
-				remote = remote.Next(n)
-			}
+	plan := make([]*Node, 0, desiredCnt)
+	for len(plan) < desiredCnt {
+		n := rit.Next()
+		if n == nil {
+			break
 		}
 
-		pi.ring[i].localReplicas = local.Path()
-		pi.ring[i].remoteReplicas = remote.Path()
+		if filter(n, plan) {
+			plan = append(plan, n)
+		}
+	}
+	return plan
+}
+
+// replicas returns replicas of the i-th token of the ring in keyspace with replication strategy stg,
+// the ring must be sorted.
+func (t *topology) replicas(stg strategy, i int) ([]*Node, error) {
+	switch stg.class {
+	case simpleStrategy, localStrategy:
+		return simpleStrategyReplicas(t.policyInfo.ring, stg, i), nil
+	case networkTopologyStrategy:
+		return networkTopologyStrategyReplicas(t.policyInfo.ring, t.dcRacks, stg, i), nil
+	default:
+		return nil, fmt.Errorf("replicas of %s are unknown", stg.class)
 	}
 }
@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"math"
 	"testing"
 
 	"github.com/kulezi/scylla-go-driver/frame"
@@ -28,11 +29,8 @@ func mockCluster(t *topology, ks, localDC string) *Cluster {
 	c := Cluster{}
 	t.localDC = localDC
 
-	if k, ok := t.keyspaces[ks]; ok {
-		t.policyInfo.Preprocess(t, k)
-	} else {
-		t.policyInfo.Preprocess(t, keyspace{})
-	}
+	c.cfg.Keyspace = ks
+	t.policyInfo.Preprocess(t)
 	c.setTopology(t)
 
 	return &c
@@ -335,3 +333,220 @@ func TestTokenAwareNetworkStrategyPolicy(t *testing.T) { //nolint:paralleltest /
 		})
 	}
 }
+
+func TestTokenAwarePolicyStatementKeyspace(t *testing.T) { //nolint:paralleltest // Can't run in parallel.
+	testCases := []struct {
+		name            string
+		top             *topology
+		defaultKeyspace string
+		keyspace        string
+		localDC         string
+		token           Token
+		expected        []string
+	}{
+		{
+			name:     "no default keyspace",
+			top:      mockTopologyTokenAwareSimpleStrategy(),
+			keyspace: "rf2",
+			token:    160,
+			expected: []string{"3", "1"},
+		},
+		{
+			name:            "replication factor different from the default keyspace",
+			top:             mockTopologyTokenAwareSimpleStrategy(),
+			defaultKeyspace: "rf2",
+			keyspace:        "rf3",
+			token:           60,
+			expected:        []string{"1", "2", "3"},
+		},
+		{
+			name:     "network topology strategy without default keyspace",
+			top:      mockTopologyTokenAwareDCAwareStrategy(),
+			keyspace: "waw/her",
+			localDC:  "waw",
+			token:    0,
+			expected: []string{"1", "4", "5", "6", "8"},
+		},
+	}
+
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		policy := NewTokenAwarePolicy(tc.localDC)
+		c := mockCluster(tc.top, tc.defaultKeyspace, tc.localDC)
+
+		t.Run(tc.name, func(t *testing.T) {
+			qi, err := c.NewLWTQueryInfo(tc.token, tc.keyspace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for offset, addr := range tc.expected {
+				if res := policy.Node(qi, offset); res == nil || res.addr != addr {
+					t.Fatalf("TestTokenAwarePolicyStatementKeyspace: offset %d: got %v but expected \"%s\"", offset, res, addr)
+				}
+			}
+			if policy.Node(qi, len(tc.expected)) != nil {
+				t.Fatalf("TestTokenAwarePolicyStatementKeyspace: plan iter didn't return nil after making the whole cycle")
+			}
+		})
+	}
+}
+
+func TestTokenAwareLWTPolicy(t *testing.T) { //nolint:paralleltest // Can't run in parallel.
+	testCases := []struct {
+		name     string
+		top      *topology
+		keyspace string
+		localDC  string
+		token    Token
+		expected []string
+	}{
+		{
+			name:     "simple strategy, replication factor = 3",
+			top:      mockTopologyTokenAwareSimpleStrategy(),
+			keyspace: "rf3",
+			token:    60,
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:     "network topology strategy, 'waw' dc with rf = 2, 'her' dc with rf = 3",
+			top:      mockTopologyTokenAwareDCAwareStrategy(),
+			keyspace: "waw/her",
+			localDC:  "waw",
+			token:    0,
+			expected: []string{"1", "4", "5", "6", "8"},
+		},
+	}
+
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		policy := NewTokenAwarePolicy(tc.localDC)
+		c := mockCluster(tc.top, tc.keyspace, tc.localDC)
+
+		t.Run(tc.name, func(t *testing.T) {
+			// Every query plan should start from the primary replica regardless of the round robin offset.
+			for iter := 0; iter < len(tc.expected)+1; iter++ {
+				qi, err := c.NewLWTQueryInfo(tc.token, tc.keyspace)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for offset, addr := range tc.expected {
+					if res := policy.Node(qi, offset).addr; res != addr {
+						t.Fatalf("TestTokenAwareLWTPolicy: in iteration %d: got \"%s\" but expected \"%s\"", iter, res, addr)
+					}
+				}
+				if policy.Node(qi, len(tc.expected)) != nil {
+					t.Fatalf("TestTokenAwareLWTPolicy: plan iter didn't return nil after making the whole cycle")
+				}
+			}
+		})
+	}
+}
+
+func TestClusterReplicas(t *testing.T) { //nolint:paralleltest // Can't run in parallel.
+	testCases := []struct {
+		name     string
+		top      *topology
+		keyspace string
+		token    Token
+		expected []string
+	}{
+		{
+			name:     "simple strategy, replication factor = 2",
+			top:      mockTopologyTokenAwareSimpleStrategy(),
+			keyspace: "rf2",
+			token:    160,
+			expected: []string{"3", "1"},
+		},
+		{
+			name:     "simple strategy, token after the last one in the ring",
+			top:      mockTopologyTokenAwareSimpleStrategy(),
+			keyspace: "rf3",
+			token:    600,
+			expected: []string{"2", "1", "3"},
+		},
+		{
+			name:     "network topology strategy, 'waw' dc with rf = 2, 'her' dc with rf = 3",
+			top:      mockTopologyTokenAwareDCAwareStrategy(),
+			keyspace: "waw/her",
+			token:    0,
+			expected: []string{"1", "5", "6", "4", "8"},
+		},
+	}
+
+	for i := 0; i < len(testCases); i++ {
+		tc := testCases[i]
+		// Replicas don't depend on the keyspace used for preprocessing.
+		c := mockCluster(tc.top, "", "")
+
+		t.Run(tc.name, func(t *testing.T) {
+			replicas, err := c.Replicas(tc.keyspace, tc.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(replicas) != len(tc.expected) {
+				t.Fatalf("TestClusterReplicas: got %d replicas but expected %d", len(replicas), len(tc.expected))
+			}
+			for i, addr := range tc.expected {
+				if res := replicas[i].addr; res != addr {
+					t.Fatalf("TestClusterReplicas: replica %d: got \"%s\" but expected \"%s\"", i, res, addr)
+				}
+			}
+		})
+	}
+
+	c := mockCluster(mockTopologyTokenAwareSimpleStrategy(), "", "")
+	if _, err := c.Replicas("unknown", 0); err == nil {
+		t.Fatal("TestClusterReplicas: expected error for unknown keyspace")
+	}
+}
+
+func TestTokenAwareQueryInfoUnknownKeyspace(t *testing.T) {
+	t.Parallel()
+	c := mockCluster(mockTopologyTokenAwareSimpleStrategy(), "", "")
+	qi, err := c.NewTokenAwareQueryInfo(160, "unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qi.tokenAware {
+		t.Fatal("TestTokenAwareQueryInfoUnknownKeyspace: expected non-token aware query info")
+	}
+}
+
+func TestClusterTokenRanges(t *testing.T) {
+	t.Parallel()
+	c := mockCluster(mockTopologyTokenAwareSimpleStrategy(), "", "")
+
+	ranges, err := c.TokenRanges("rf2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		start, end Token
+		replicas   []string
+	}{
+		{math.MinInt64, 50, []string{"2", "1"}},
+		{50, 100, []string{"1", "2"}},
+		{100, 150, []string{"2", "3"}},
+		{150, 200, []string{"3", "1"}},
+		{200, 250, []string{"1", "2"}},
+		{250, 300, []string{"2", "3"}},
+		{300, 400, []string{"3", "1"}},
+		{400, 500, []string{"1", "2"}},
+		{500, math.MaxInt64, []string{"2", "1"}},
+	}
+	if len(ranges) != len(expected) {
+		t.Fatalf("TestClusterTokenRanges: got %d ranges but expected %d", len(ranges), len(expected))
+	}
+	for i, e := range expected {
+		r := ranges[i]
+		if r.Start != e.start || r.End != e.end {
+			t.Fatalf("TestClusterTokenRanges: range %d: got (%d, %d] but expected (%d, %d]", i, r.Start, r.End, e.start, e.end)
+		}
+		for j, addr := range e.replicas {
+			if r.Replicas[j].addr != addr {
+				t.Fatalf("TestClusterTokenRanges: range %d replica %d: got \"%s\" but expected \"%s\"", i, j, r.Replicas[j].addr, addr)
+			}
+		}
+	}
+}
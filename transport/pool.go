@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math"
 	"net"
 	"time"
 
@@ -88,14 +87,26 @@ func (p *ConnPool) LeastBusyConn() (*Conn, error) {
 }
 
 func (p *ConnPool) shardOf(token Token) int {
-	shards := uint64(p.nrShards)
-	z := uint64(token+math.MinInt64) << p.msbIgnore
-	lo := z & 0xffffffff
-	hi := (z >> 32) & 0xffffffff
-	mul1 := lo * shards
-	mul2 := hi * shards
-	sum := (mul1 >> 32) + mul2
-	return int(sum >> 32)
+	return int(ShardOf(p.shardInfo(), token))
+}
+
+// shardInfo returns sharding parameters of the node, Shard is not set.
+func (p *ConnPool) shardInfo() ShardInfo {
+	return ShardInfo{
+		NrShards:  uint16(p.nrShards),
+		MsbIgnore: p.msbIgnore,
+	}
+}
+
+// openConns returns the number of open connections to each shard.
+func (p *ConnPool) openConns() []int {
+	res := make([]int, len(p.conns))
+	for i := range p.conns {
+		if p.loadConn(i) != nil {
+			res[i] = 1
+		}
+	}
+	return res
 }
 
 func (p *ConnPool) storeConn(conn *Conn) {
@@ -227,10 +238,7 @@ func (r *PoolRefiller) fill(ctx context.Context) {
 		return
 	}
 
-	si := ShardInfo{
-		NrShards:  uint16(r.pool.nrShards),
-		MsbIgnore: r.pool.msbIgnore,
-	}
+	si := r.pool.shardInfo()
 
 	for i := 0; i < r.pool.nrShards; i++ {
 		if r.pool.loadConn(i) != nil {
@@ -1,6 +1,9 @@
 package transport
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/kulezi/scylla-go-driver/frame"
 	. "github.com/kulezi/scylla-go-driver/frame/request"
 	. "github.com/kulezi/scylla-go-driver/frame/response"
@@ -15,11 +18,25 @@ type Statement struct {
 	PageSize          frame.Int
 	Consistency       frame.Consistency
 	SerialConsistency frame.Consistency
-	Tracing           bool
-	Compression       bool
-	Idempotent        bool
-	NoSkipMetadata    bool
-	Metadata          *frame.ResultMetadata
+	// Timestamp is the default timestamp of the statement in microseconds, 0 means it's set by the coordinator.
+	Timestamp      frame.Long
+	Tracing        bool
+	Compression    bool
+	Idempotent     bool
+	NoSkipMetadata bool
+	// Timeout overrides ConnConfig.RequestTimeout if greater than 0.
+	Timeout time.Duration
+	// CustomPayload is sent along with the request, it's not copied by Clone.
+	CustomPayload frame.BytesMap
+	// Names of Values, if set values are sent with names (WithNamesForValues) instead of by position.
+	Names []string
+	// Keyspace of the table a prepared statement refers to, its replication strategy is used for token aware routing.
+	Keyspace string
+	// BindMarkers describes bind markers of a prepared statement, in the order of Values.
+	BindMarkers []frame.ColumnSpec
+	// LWT is set for prepared lightweight transactions if the node supports marking them in PREPARED metadata.
+	LWT      bool
+	Metadata *frame.ResultMetadata
 }
 
 // Clone makes new Values to avoid data overwrite in binding.
@@ -32,18 +49,33 @@ func (s Statement) Clone() Statement {
 			c.Values[i] = s.Values[i].Clone()
 		}
 	}
+	if s.Names != nil {
+		c.Names = make([]string, len(s.Names))
+		copy(c.Names, s.Names)
+	}
 	return c
 }
 
+func (s *Statement) requestOptions() requestOptions {
+	return requestOptions{
+		compress:      s.Compression,
+		tracing:       s.Tracing,
+		timeout:       s.Timeout,
+		customPayload: s.CustomPayload,
+	}
+}
+
 func makeQuery(s Statement, pagingState frame.Bytes) Query {
 	return Query{
 		Query:       s.Content,
 		Consistency: s.Consistency,
 		Options: frame.QueryOptions{
 			Values:            s.Values,
+			Names:             s.Names,
 			SerialConsistency: s.SerialConsistency,
 			PagingState:       pagingState,
 			PageSize:          s.PageSize,
+			Timestamp:         s.Timestamp,
 		},
 	}
 }
@@ -58,6 +90,7 @@ func makeExecute(s Statement, pagingState frame.Bytes) Execute {
 			SerialConsistency: s.SerialConsistency,
 			PagingState:       pagingState,
 			PageSize:          s.PageSize,
+			Timestamp:         s.Timestamp,
 		},
 	}
 	if s.NoSkipMetadata {
@@ -67,6 +100,85 @@ func makeExecute(s Statement, pagingState frame.Bytes) Execute {
 	return res
 }
 
+// BatchStatement groups statements that are sent together in a single BATCH request.
+type BatchStatement struct {
+	Type              frame.BatchTypeFlag
+	Statements        []Statement
+	Consistency       frame.Consistency
+	SerialConsistency frame.Consistency
+	Timestamp         frame.Long
+	Tracing           bool
+	Compression       bool
+	Idempotent        bool
+	// Timeout overrides ConnConfig.RequestTimeout if greater than 0.
+	Timeout time.Duration
+	// CustomPayload is sent along with the request, it's not copied by Clone.
+	CustomPayload frame.BytesMap
+}
+
+// Clone makes new Values for every statement to avoid data overwrite in binding.
+func (b BatchStatement) Clone() BatchStatement {
+	c := b
+	c.Statements = make([]Statement, len(b.Statements))
+	for i := range b.Statements {
+		c.Statements[i] = b.Statements[i].Clone()
+	}
+	return c
+}
+
+func (b *BatchStatement) requestOptions() requestOptions {
+	return requestOptions{
+		compress:      b.Compression,
+		tracing:       b.Tracing,
+		timeout:       b.Timeout,
+		customPayload: b.CustomPayload,
+	}
+}
+
+// checkNames verifies that no statement of the batch has named values,
+// servers ignore names of values in BATCH (CASSANDRA-10246) and would bind them by position.
+func (b *BatchStatement) checkNames() error {
+	for i := range b.Statements {
+		if b.Statements[i].Names != nil {
+			return fmt.Errorf("statement %d of the batch has named values, named values aren't supported in batches", i)
+		}
+	}
+	return nil
+}
+
+func makeBatch(b BatchStatement) Batch {
+	res := Batch{
+		Type:              b.Type,
+		Queries:           make([]BatchQuery, len(b.Statements)),
+		Consistency:       b.Consistency,
+		SerialConsistency: b.SerialConsistency,
+		Timestamp:         b.Timestamp,
+	}
+	for i, s := range b.Statements {
+		if s.ID != nil {
+			res.Queries[i] = BatchQuery{
+				Kind:     1,
+				Prepared: s.ID,
+				Values:   s.Values,
+			}
+		} else {
+			res.Queries[i] = BatchQuery{
+				Kind:   0,
+				Query:  s.Content,
+				Values: s.Values,
+			}
+		}
+	}
+	if b.SerialConsistency != 0 {
+		res.Flags |= frame.WithSerialConsistency
+	}
+	if b.Timestamp != 0 {
+		res.Flags |= frame.WithDefaultTimestamp
+	}
+
+	return res
+}
+
 func makeStatement(cql string) Statement {
 	return Statement{
 		Content:     cql,
@@ -82,9 +194,24 @@ type QueryResult struct {
 	PagingState  frame.Bytes
 	ColSpec      []frame.ColumnSpec
 	SchemaChange *SchemaChange
+	// CustomPayload holds the custom payload sent by the server along with the result.
+	CustomPayload frame.BytesMap
 }
 
-func MakeQueryResult(res frame.Response, meta *frame.ResultMetadata) (QueryResult, error) {
+// MakeQueryResult converts response to a request to QueryResult,
+// meta describes the result columns if they were skipped in the response.
+func MakeQueryResult(resp response, meta *frame.ResultMetadata) (QueryResult, error) {
+	res, err := makeQueryResult(resp.Response, meta)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	res.TracingID = resp.TracingID
+	res.Warnings = resp.Warnings
+	res.CustomPayload = resp.CustomPayload
+	return res, nil
+}
+
+func makeQueryResult(res frame.Response, meta *frame.ResultMetadata) (QueryResult, error) {
 	switch v := res.(type) {
 	case *RowsResult:
 		ret := QueryResult{
@@ -94,6 +221,9 @@ func MakeQueryResult(res frame.Response, meta *frame.ResultMetadata) (QueryResul
 			ColSpec:      v.Metadata.Columns,
 		}
 		if meta != nil && meta.Columns != nil {
+			if ret.ColSpec == nil {
+				ret.ColSpec = meta.Columns
+			}
 			for i := range ret.Rows {
 				for j := range meta.Columns {
 					ret.Rows[i][j].Type = &meta.Columns[j].Type
@@ -1,6 +1,8 @@
 package transport
 
 import (
+	"math"
+	"math/bits"
 	"math/rand"
 
 	"github.com/kulezi/scylla-go-driver/transport/murmur"
@@ -49,3 +51,49 @@ func MurmurToken(partitionKey []byte) Token {
 	h := murmur.Hash3(partitionKey)
 	return Token(h)
 }
+
+// ShardOf returns the shard owning token t on a node with si.NrShards shards ignoring si.MsbIgnore most significant bits,
+// si.Shard is not used.
+func ShardOf(si ShardInfo, t Token) uint16 {
+	z := uint64(t+math.MinInt64) << si.MsbIgnore
+	hi, _ := bits.Mul64(z, uint64(si.NrShards))
+	return uint16(hi)
+}
+
+// NextShardToken returns the smallest token greater than t owned by a different shard than t, see ShardOf.
+// Tokens owned by consecutive shards form stripes repeated every 2^(64-MsbIgnore) tokens.
+// It reports false if all tokens greater than t are owned by the shard of t.
+func NextShardToken(si ShardInfo, t Token) (Token, bool) {
+	if si.NrShards <= 1 {
+		return 0, false
+	}
+	w := 64 - uint(si.MsbIgnore)
+	// u is the position of t on the ring starting from math.MinInt64, the stripe is selected by its w-bit prefix.
+	u := uint64(t + math.MinInt64)
+	var stripe uint64
+	if w < 64 {
+		stripe = u >> w << w
+	}
+
+	s := uint64(ShardOf(si, t)) + 1
+	if s < uint64(si.NrShards) {
+		// The first position in stripe owned by shard s is ceil(s * 2^w / NrShards).
+		var hi, lo uint64
+		if w == 64 {
+			hi = s
+		} else {
+			hi, lo = bits.Mul64(s, 1<<w)
+		}
+		q, r := bits.Div64(hi, lo, uint64(si.NrShards))
+		if r != 0 {
+			q++
+		}
+		return Token(stripe+q) - math.MinInt64, true
+	}
+
+	// Shard 0 of the next stripe.
+	if w == 64 || stripe+1<<w == 0 {
+		return 0, false
+	}
+	return Token(stripe+1<<w) - math.MinInt64, true
+}
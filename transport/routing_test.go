@@ -1,6 +1,9 @@
 package transport
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestShardPortIterator(t *testing.T) {
 	t.Parallel()
@@ -25,3 +28,34 @@ func TestShardPortIterator(t *testing.T) {
 		}
 	}
 }
+
+func TestNextShardToken(t *testing.T) {
+	t.Parallel()
+	tokens := []Token{math.MinInt64, math.MinInt64 + 1, -1 << 40, -1, 0, 1, 1 << 40, math.MaxInt64 - 1}
+	for _, msb := range []uint8{0, 1, 12} {
+		for shards := 1; shards < 20; shards++ {
+			si := ShardInfo{
+				NrShards:  uint16(shards),
+				MsbIgnore: msb,
+			}
+			for _, token := range tokens {
+				next, ok := NextShardToken(si, token)
+				if !ok {
+					if shards > 1 && msb > 0 && token < 0 {
+						t.Fatalf("%+v: no token after %d owned by other shard", si, token)
+					}
+					continue
+				}
+				if next <= token {
+					t.Fatalf("%+v: next token %d is not greater than %d", si, next, token)
+				}
+				if ShardOf(si, next) == ShardOf(si, token) {
+					t.Fatalf("%+v: token %d and next token %d are owned by the same shard %d", si, token, next, ShardOf(si, token))
+				}
+				if ShardOf(si, next-1) != ShardOf(si, token) {
+					t.Fatalf("%+v: token %d before next token %d is not owned by shard %d", si, next-1, next, ShardOf(si, token))
+				}
+			}
+		}
+	}
+}
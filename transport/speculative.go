@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SpeculativeExecutionPolicy decides when to send an idempotent request to another node
+// while the previous executions haven't responded yet.
+type SpeculativeExecutionPolicy interface {
+	// NextExecution returns the delay after which the n-th additional execution (counting from 1) should be started.
+	// It returns false if no more executions should be started.
+	NextExecution(n int) (time.Duration, bool)
+	// Observe is called with the latency of each successful execution.
+	Observe(latency time.Duration)
+}
+
+// ConstantSpeculativeExecutionPolicy starts up to MaxExecutions additional executions, each Delay after the previous one.
+type ConstantSpeculativeExecutionPolicy struct {
+	Delay         time.Duration
+	MaxExecutions int
+}
+
+func NewConstantSpeculativeExecutionPolicy(delay time.Duration, maxExecutions int) SpeculativeExecutionPolicy {
+	return &ConstantSpeculativeExecutionPolicy{
+		Delay:         delay,
+		MaxExecutions: maxExecutions,
+	}
+}
+
+func (p *ConstantSpeculativeExecutionPolicy) NextExecution(n int) (time.Duration, bool) {
+	return p.Delay, n <= p.MaxExecutions
+}
+
+func (*ConstantSpeculativeExecutionPolicy) Observe(time.Duration) {}
+
+const (
+	percentileSamples   = 1024
+	percentileRefresh   = 64
+	percentileMinSample = 100
+)
+
+// PercentileSpeculativeExecutionPolicy starts up to maxExecutions additional executions,
+// each after the given percentile of recently observed latencies elapses since the previous one.
+// Until enough latencies are observed no additional executions are started.
+type PercentileSpeculativeExecutionPolicy struct {
+	percentile    float64
+	maxExecutions int
+
+	mu      sync.Mutex // mu guards all fields below.
+	samples []time.Duration
+	next    int // next is the index of the oldest sample once samples are full.
+	fresh   int // fresh is the number of samples observed since delay was computed.
+	delay   time.Duration
+}
+
+// NewPercentileSpeculativeExecutionPolicy creates a policy with percentile in range (0, 100].
+func NewPercentileSpeculativeExecutionPolicy(percentile float64, maxExecutions int) SpeculativeExecutionPolicy {
+	return &PercentileSpeculativeExecutionPolicy{
+		percentile:    percentile,
+		maxExecutions: maxExecutions,
+		samples:       make([]time.Duration, 0, percentileSamples),
+	}
+}
+
+func (p *PercentileSpeculativeExecutionPolicy) NextExecution(n int) (time.Duration, bool) {
+	if n > p.maxExecutions {
+		return 0, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.samples) < percentileMinSample {
+		return 0, false
+	}
+	// Sorting samples on every request would be too expensive, the delay is refreshed periodically instead.
+	if p.delay == 0 || p.fresh >= percentileRefresh {
+		p.delay = p.computeDelay()
+		p.fresh = 0
+	}
+	return p.delay, true
+}
+
+func (p *PercentileSpeculativeExecutionPolicy) Observe(latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.samples) < cap(p.samples) {
+		p.samples = append(p.samples, latency)
+	} else {
+		p.samples[p.next] = latency
+		p.next = (p.next + 1) % len(p.samples)
+	}
+
+	p.fresh++
+}
+
+func (p *PercentileSpeculativeExecutionPolicy) computeDelay() time.Duration {
+	s := make([]time.Duration, len(p.samples))
+	copy(s, p.samples)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+
+	idx := int(float64(len(s))*p.percentile/100+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(s) {
+		idx = len(s) - 1
+	}
+	return s[idx]
+}
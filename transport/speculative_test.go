@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConstantSpeculativeExecutionPolicy(t *testing.T) {
+	t.Parallel()
+	p := NewConstantSpeculativeExecutionPolicy(10*time.Millisecond, 2)
+
+	for n := 1; n <= 2; n++ {
+		if d, ok := p.NextExecution(n); !ok || d != 10*time.Millisecond {
+			t.Fatalf("execution %d: expected 10ms, got %v %v", n, d, ok)
+		}
+	}
+	if _, ok := p.NextExecution(3); ok {
+		t.Fatal("expected no more than 2 additional executions")
+	}
+}
+
+func TestPercentileSpeculativeExecutionPolicy(t *testing.T) {
+	t.Parallel()
+	p := NewPercentileSpeculativeExecutionPolicy(99, 1)
+
+	if _, ok := p.NextExecution(1); ok {
+		t.Fatal("expected no executions before any latencies are observed")
+	}
+
+	for i := 1; i <= 100; i++ {
+		p.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if d, ok := p.NextExecution(1); !ok || d != 99*time.Millisecond {
+		t.Fatalf("expected 99ms, got %v %v", d, ok)
+	}
+	if _, ok := p.NextExecution(2); ok {
+		t.Fatal("expected no more than 1 additional execution")
+	}
+
+	// Old samples are replaced by new ones.
+	for i := 0; i < percentileSamples; i++ {
+		p.Observe(time.Millisecond)
+	}
+	if d, ok := p.NextExecution(1); !ok || d != time.Millisecond {
+		t.Fatalf("expected 1ms, got %v %v", d, ok)
+	}
+}
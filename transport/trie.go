@@ -18,7 +18,8 @@ func trieRoot() trie {
 func newTrie(node *Node, parent *trie) *trie {
 	return &trie{
 		next: make(map[frame.UUID]*trie),
-		path: append(parent.path, node),
+		// Siblings mustn't share the backing array of the parent's path.
+		path: append(parent.path[:len(parent.path):len(parent.path)], node),
 	}
 }
 